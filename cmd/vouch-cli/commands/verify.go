@@ -6,29 +6,32 @@ import (
 	"log"
 	"os"
 
-	"github.com/slyt3/Vouch/internal/assert"
-	"github.com/slyt3/Vouch/internal/crypto"
-	"github.com/slyt3/Vouch/internal/ledger/audit"
-	"github.com/slyt3/Vouch/internal/ledger/store"
+	"github.com/yourname/vouch/internal/assert"
+	"github.com/yourname/vouch/internal/crypto"
+	"github.com/yourname/vouch/internal/ledger/audit"
+	"github.com/yourname/vouch/internal/ledger/store"
 )
 
 func VerifyCommand() {
 	// Parse flags
 	verifyFlags := flag.NewFlagSet("verify", flag.ExitOnError)
 	skipLive := verifyFlags.Bool("skip-live", false, "Skip live verification of Bitcoin anchors")
+	storeDSN := verifyFlags.String("store", "sqlite://vouch.db",
+		"ledger storage backend: sqlite://path, etcd://host:port,..., or postgres://...")
 	_ = verifyFlags.Parse(os.Args[2:])
 
 	// Open database
-	db, err := store.NewDB("vouch.db")
+	db, err := store.NewDB(*storeDSN)
 	if err := assert.Check(err == nil, "failed to open database: %v", err); err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
 	defer db.Close()
 
-	// Load signer
-	signer, err := crypto.NewSigner(".vouch_key")
-	if err := assert.Check(err == nil, "failed to load signer: %v", err); err != nil {
-		log.Fatalf("Failed to load signer: %v", err)
+	// Load the group's public key material; verification never needs a
+	// participant's secret share.
+	group, err := crypto.LoadGroupKey(".vouch_key")
+	if err := assert.Check(err == nil, "failed to load group key: %v", err); err != nil {
+		log.Fatalf("Failed to load group key: %v", err)
 	}
 
 	// Get current run ID
@@ -45,7 +48,7 @@ func VerifyCommand() {
 	fmt.Printf("Verifying chain for run: %s\n", runID[:8])
 
 	// Verify chain
-	result, err := audit.VerifyChain(db, runID, signer)
+	result, err := audit.VerifyChain(db, runID, group)
 	if err != nil {
 		log.Fatalf("Verification error: %v", err)
 	}
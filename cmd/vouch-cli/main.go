@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/yourname/vouch/cmd/vouch-cli/commands"
+	"github.com/yourname/vouch/internal/ledger"
+	"github.com/yourname/vouch/internal/ledger/store"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "verify":
+		commands.VerifyCommand()
+	case "anchor":
+		anchorCommand()
+	default:
+		fmt.Printf("Unknown command: %s\n", os.Args[1])
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Println("vouch - Vouch ledger command line tool")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  vouch verify [--skip-live] [--store DSN]   Validate the hash chain and its Bitcoin anchors")
+	fmt.Println("  vouch anchor [--store DSN]                  Force-flush the pending anchor batch")
+	fmt.Println()
+	fmt.Println("  --store defaults to sqlite://vouch.db; also accepts etcd://host:port,... or postgres://...")
+}
+
+// anchorCommand forces an immediate Bitcoin anchor of whatever chain heads
+// are pending, instead of waiting for --anchor-interval to elapse. It
+// shares ledger.FlushAnchor with the worker's own anchor loop, so it works
+// whether or not a vouchd is currently running against this database.
+func anchorCommand() {
+	anchorFlags := flag.NewFlagSet("anchor", flag.ExitOnError)
+	storeDSN := anchorFlags.String("store", "sqlite://vouch.db",
+		"ledger storage backend: sqlite://path, etcd://host:port,..., or postgres://...")
+	_ = anchorFlags.Parse(os.Args[2:])
+
+	db, err := store.NewDB(*storeDSN)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	runID, err := db.GetRunID()
+	if err != nil {
+		log.Fatalf("Failed to get run id: %v", err)
+	}
+	if runID == "" {
+		fmt.Println("No runs found in database")
+		return
+	}
+
+	fmt.Println("Flushing pending anchor batch...")
+	if err := ledger.FlushAnchor(db, runID); err != nil {
+		log.Fatalf("Failed to flush anchor: %v", err)
+	}
+	fmt.Println("Anchor flushed.")
+}
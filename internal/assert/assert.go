@@ -0,0 +1,20 @@
+// Package assert provides lightweight precondition checks that return an
+// error instead of panicking, so invariant violations can be surfaced to
+// callers as ordinary Go errors rather than crashing the process.
+package assert
+
+import "fmt"
+
+// Check returns nil if cond is true. Otherwise it formats format/args with
+// fmt.Errorf and returns the result. Callers typically use this to guard
+// invariants at the top of a function:
+//
+//	if err := assert.Check(x != nil, "x must not be nil"); err != nil {
+//		return err
+//	}
+func Check(cond bool, format string, args ...interface{}) error {
+	if cond {
+		return nil
+	}
+	return fmt.Errorf(format, args...)
+}
@@ -0,0 +1,630 @@
+// Package crypto implements FROST (Flexible Round-Optimized Schnorr
+// Threshold signatures) over Ed25519: a (t, n) group of participants each
+// hold a secret share, and any t of them can jointly produce a single
+// standard Ed25519 signature over a message, verifiable with stock
+// ed25519.Verify against one aggregate public key. No participant's share
+// alone can forge a signature, and no fewer than t participants can
+// either.
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"filippo.io/edwards25519"
+)
+
+// Share is one participant's secret signing share, produced by
+// GenerateThresholdKeys (or replaced in place by RefreshShares).
+type Share struct {
+	ParticipantID int
+	Secret        *edwards25519.Scalar
+}
+
+// GroupKey is the public output of threshold key generation: the
+// aggregate public key every co-signed event verifies against, plus every
+// participant's individual verification share (secret_i * B), which lets
+// a coordinator validate a partial signature before aggregating it.
+type GroupKey struct {
+	Threshold int
+	Shares    map[int]*edwards25519.Point // participant id -> verification share
+	PublicKey ed25519.PublicKey
+}
+
+// Verify reports whether sig is a valid signature over message under the
+// group's aggregate public key. A FROST-aggregated signature is a
+// standard Ed25519 signature, so this is just ed25519.Verify.
+func (g *GroupKey) Verify(message, sig []byte) bool {
+	return ed25519.Verify(g.PublicKey, message, sig)
+}
+
+// GenerateThresholdKeys runs a trusted-dealer FROST key generation: it
+// samples a random degree-(t-1) polynomial, evaluates it at participant
+// ids 1..n to produce each share, and derives the aggregate public key
+// from the polynomial's constant term. (A fully distributed key
+// generation, where no single party ever learns the joint secret, is a
+// superset of this protocol and is not implemented here.)
+func GenerateThresholdKeys(n, t int) ([]*Share, *GroupKey, error) {
+	if t < 1 || t > n {
+		return nil, nil, fmt.Errorf("threshold %d must be between 1 and %d", t, n)
+	}
+
+	coeffs := make([]*edwards25519.Scalar, t)
+	for i := range coeffs {
+		s, err := randomScalar()
+		if err != nil {
+			return nil, nil, fmt.Errorf("sample polynomial coefficient: %w", err)
+		}
+		coeffs[i] = s
+	}
+
+	shares := make([]*Share, n)
+	verifShares := make(map[int]*edwards25519.Point, n)
+	for id := 1; id <= n; id++ {
+		secret := evalPolynomial(coeffs, id)
+		shares[id-1] = &Share{ParticipantID: id, Secret: secret}
+		verifShares[id] = new(edwards25519.Point).ScalarBaseMult(secret)
+	}
+
+	group := &GroupKey{
+		Threshold: t,
+		Shares:    verifShares,
+		PublicKey: ed25519.PublicKey(new(edwards25519.Point).ScalarBaseMult(coeffs[0]).Bytes()),
+	}
+	return shares, group, nil
+}
+
+// RefreshShares performs proactive resharing: it samples a fresh
+// degree-(t-1) polynomial whose constant term is zero and adds its
+// evaluation at each participant's id to that participant's existing
+// share. Because the zero-polynomial's Lagrange-interpolated constant
+// term is zero, the aggregate public key — and every signature already
+// verified against it — is unchanged, while any share captured before the
+// refresh becomes useless against the refreshed ones. It requires every
+// participant's current share, since each one is replaced.
+//
+// Each supplied share is checked against group's existing verification
+// share (secret_i * B) before it's trusted: a caller that doesn't already
+// hold a participant's real secret can't get it folded into the refresh
+// by simply naming that participant's id.
+func RefreshShares(shares []*Share, group *GroupKey) ([]*Share, *GroupKey, error) {
+	if len(shares) != len(group.Shares) {
+		return nil, nil, fmt.Errorf("resharing requires all %d participants' shares, got %d", len(group.Shares), len(shares))
+	}
+	seen := make(map[int]bool, len(shares))
+	for _, sh := range shares {
+		if seen[sh.ParticipantID] {
+			return nil, nil, fmt.Errorf("participant %d supplied more than once", sh.ParticipantID)
+		}
+		seen[sh.ParticipantID] = true
+
+		verif, ok := group.Shares[sh.ParticipantID]
+		if !ok {
+			return nil, nil, fmt.Errorf("participant %d is not a member of this group", sh.ParticipantID)
+		}
+		got := new(edwards25519.Point).ScalarBaseMult(sh.Secret)
+		if got.Equal(verif) != 1 {
+			return nil, nil, fmt.Errorf("share for participant %d does not match its recorded verification share", sh.ParticipantID)
+		}
+	}
+
+	t := group.Threshold
+	coeffs := make([]*edwards25519.Scalar, t)
+	coeffs[0] = edwards25519.NewScalar() // zero constant term: the secret itself is unchanged
+	for i := 1; i < t; i++ {
+		s, err := randomScalar()
+		if err != nil {
+			return nil, nil, fmt.Errorf("sample resharing coefficient: %w", err)
+		}
+		coeffs[i] = s
+	}
+
+	refreshed := make([]*Share, len(shares))
+	verifShares := make(map[int]*edwards25519.Point, len(shares))
+	for i, sh := range shares {
+		delta := evalPolynomial(coeffs, sh.ParticipantID)
+		newSecret := edwards25519.NewScalar().Add(sh.Secret, delta)
+		refreshed[i] = &Share{ParticipantID: sh.ParticipantID, Secret: newSecret}
+		verifShares[sh.ParticipantID] = new(edwards25519.Point).ScalarBaseMult(newSecret)
+	}
+
+	newGroup := &GroupKey{Threshold: t, Shares: verifShares, PublicKey: group.PublicKey}
+	return refreshed, newGroup, nil
+}
+
+// NonceCommitment is round 1 of FROST signing: a participant's public
+// hiding and binding nonce commitments for one signing session. The
+// underlying nonce scalars stay secret, held by ThresholdSigner until
+// Round2 consumes them.
+type NonceCommitment struct {
+	ParticipantID   int
+	Hiding, Binding *edwards25519.Point
+}
+
+// PartialSignature is round 2 of FROST signing: one participant's share
+// of the final signature, computed from its Round1 nonces, its Lagrange
+// coefficient within the signing set, and every signing participant's
+// published commitments.
+type PartialSignature struct {
+	ParticipantID int
+	Z             *edwards25519.Scalar
+}
+
+type nonceSecret struct {
+	hiding, binding *edwards25519.Scalar
+}
+
+// ThresholdSigner is one participant's handle on a FROST signing group:
+// its own secret Share plus the GroupKey needed to run the two-round
+// signing protocol, verify its own work, and participate in rekeying.
+type ThresholdSigner struct {
+	mu     sync.Mutex
+	share  *Share
+	group  *GroupKey
+	nonces map[string]nonceSecret // session ID -> this participant's round-1 secrets, consumed by Round2
+}
+
+// NewThresholdSigner wraps an already-generated share and group key. Most
+// callers should use LoadThresholdSigner instead, which also handles
+// persistence.
+func NewThresholdSigner(share *Share, group *GroupKey) *ThresholdSigner {
+	return &ThresholdSigner{share: share, group: group, nonces: make(map[string]nonceSecret)}
+}
+
+// ParticipantID returns this signer's id within its group.
+func (s *ThresholdSigner) ParticipantID() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.share.ParticipantID
+}
+
+// Share returns this signer's current secret share, e.g. to seed the
+// all-participants set Rekey needs.
+func (s *ThresholdSigner) Share() *Share {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.share
+}
+
+// GroupKey returns the group's current public key material.
+func (s *ThresholdSigner) GroupKey() *GroupKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.group
+}
+
+// PublicKeyHex returns the group's aggregate public key, hex-encoded.
+func (s *ThresholdSigner) PublicKeyHex() string {
+	return hex.EncodeToString(s.GroupKey().PublicKey)
+}
+
+// Round1 samples fresh hiding and binding nonces for sessionID and
+// returns their public commitments. sessionID must be unique per signing
+// attempt: reusing nonces across sessions leaks the participant's share.
+func (s *ThresholdSigner) Round1(sessionID string) (*NonceCommitment, error) {
+	hiding, err := randomScalar()
+	if err != nil {
+		return nil, fmt.Errorf("sample hiding nonce: %w", err)
+	}
+	binding, err := randomScalar()
+	if err != nil {
+		return nil, fmt.Errorf("sample binding nonce: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nonces[sessionID] = nonceSecret{hiding: hiding, binding: binding}
+	return &NonceCommitment{
+		ParticipantID: s.share.ParticipantID,
+		Hiding:        new(edwards25519.Point).ScalarBaseMult(hiding),
+		Binding:       new(edwards25519.Point).ScalarBaseMult(binding),
+	}, nil
+}
+
+// Round2 computes this participant's partial signature over message,
+// given the full set of signing participants' Round1 commitments
+// (including its own). It consumes (and discards) the Round1 nonces for
+// sessionID, so a session can only be completed once.
+func (s *ThresholdSigner) Round2(sessionID string, message []byte, commitments []*NonceCommitment) (*PartialSignature, error) {
+	s.mu.Lock()
+	nonce, ok := s.nonces[sessionID]
+	delete(s.nonces, sessionID)
+	participantID := s.share.ParticipantID
+	secret := s.share.Secret
+	groupPub := s.group.PublicKey
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("no round-1 nonces for session %s (Round1 not called, or already consumed)", sessionID)
+	}
+
+	groupR, rhos, err := groupCommitment(commitments, message)
+	if err != nil {
+		return nil, err
+	}
+	rho, ok := rhos[participantID]
+	if !ok {
+		return nil, fmt.Errorf("session %s: participant %d's commitment is not in the signing set", sessionID, participantID)
+	}
+
+	ids := make([]int, len(commitments))
+	for i, c := range commitments {
+		ids[i] = c.ParticipantID
+	}
+	lambda, err := lagrangeCoefficient(participantID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := challengeScalar(groupR, groupPub, message)
+	if err != nil {
+		return nil, err
+	}
+
+	// z_i = d_i + rho_i*e_i + lambda_i*c*secret_i
+	z := edwards25519.NewScalar().Add(nonce.hiding, edwards25519.NewScalar().Multiply(rho, nonce.binding))
+	z.Add(z, edwards25519.NewScalar().Multiply(edwards25519.NewScalar().Multiply(lambda, c), secret))
+
+	return &PartialSignature{ParticipantID: participantID, Z: z}, nil
+}
+
+// CanSignSolo reports whether this signer's group is the degenerate
+// 1-of-1 case SignSolo handles alone. Callers that admit events without
+// an external co-signing ceremony (see ledger.Worker.admit) must check
+// this before calling SignSolo, since a t>1 group always fails it.
+func (s *ThresholdSigner) CanSignSolo() bool {
+	group := s.GroupKey()
+	return group.Threshold == 1 && len(group.Shares) == 1
+}
+
+// SignSolo produces a standalone Ed25519 signature by running both FROST
+// rounds against itself. It only works for a degenerate 1-of-1 group —
+// the one LoadThresholdSigner bootstraps by default — since a true t>1
+// group requires external participants' commitments and partial
+// signatures (see Round1, Round2, AggregateSignature) and cannot be
+// completed by one participant alone.
+func (s *ThresholdSigner) SignSolo(message []byte) ([]byte, error) {
+	group := s.GroupKey()
+	if group.Threshold != 1 || len(group.Shares) != 1 {
+		return nil, fmt.Errorf("SignSolo requires a 1-of-1 group; this group is %d-of-%d and needs external participants co-signing via Round1/Round2", group.Threshold, len(group.Shares))
+	}
+
+	sessionID := hex.EncodeToString(message)
+	commitment, err := s.Round1(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	partial, err := s.Round2(sessionID, message, []*NonceCommitment{commitment})
+	if err != nil {
+		return nil, err
+	}
+	return AggregateSignature(group, []*NonceCommitment{commitment}, message, []*PartialSignature{partial})
+}
+
+// AggregateSignature combines t participants' partial signatures
+// (collected by the coordinator after Round2) into a standard 64-byte
+// Ed25519 signature, verifiable against group's aggregate public key with
+// GroupKey.Verify (equivalently, stock ed25519.Verify).
+func AggregateSignature(group *GroupKey, commitments []*NonceCommitment, message []byte, partials []*PartialSignature) ([]byte, error) {
+	groupR, _, err := groupCommitment(commitments, message)
+	if err != nil {
+		return nil, err
+	}
+
+	z := edwards25519.NewScalar()
+	for _, p := range partials {
+		z.Add(z, p.Z)
+	}
+
+	sig := make([]byte, ed25519.SignatureSize)
+	copy(sig[:32], groupR.Bytes())
+	copy(sig[32:], z.Bytes())
+	return sig, nil
+}
+
+// Rekey runs proactive resharing (RefreshShares) across every
+// participant's current share, replaces this signer's own share and
+// group verification data in place, persists the result to path, and
+// returns every participant's refreshed share so a coordinator can
+// redistribute them out of band. The aggregate public key is unchanged
+// by design: past chain signatures still verify against it.
+func (s *ThresholdSigner) Rekey(path string, allShares []*Share) ([]*Share, *GroupKey, error) {
+	group := s.GroupKey()
+
+	refreshed, newGroup, err := RefreshShares(allShares, group)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mu.Lock()
+	var mine *Share
+	for _, sh := range refreshed {
+		if sh.ParticipantID == s.share.ParticipantID {
+			mine = sh
+		}
+	}
+	if mine == nil {
+		s.mu.Unlock()
+		return nil, nil, fmt.Errorf("this participant (%d) is not among the resharing set", s.share.ParticipantID)
+	}
+	s.share = mine
+	s.group = newGroup
+	s.mu.Unlock()
+
+	if err := persistKeyShareFile(path, mine, newGroup); err != nil {
+		return nil, nil, err
+	}
+	return refreshed, newGroup, nil
+}
+
+// groupCommitment computes the FROST group nonce commitment R and each
+// signing participant's binding factor rho_i for message, per the
+// published commitment set: R = sum_i (D_i + rho_i * E_i).
+func groupCommitment(commitments []*NonceCommitment, message []byte) (*edwards25519.Point, map[int]*edwards25519.Scalar, error) {
+	sorted := append([]*NonceCommitment(nil), commitments...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ParticipantID < sorted[j].ParticipantID })
+
+	rhos := make(map[int]*edwards25519.Scalar, len(sorted))
+	r := edwards25519.NewIdentityPoint()
+	for _, c := range sorted {
+		rho, err := bindingFactor(c.ParticipantID, sorted, message)
+		if err != nil {
+			return nil, nil, err
+		}
+		rhos[c.ParticipantID] = rho
+
+		term := new(edwards25519.Point).ScalarMult(rho, c.Binding)
+		term.Add(term, c.Hiding)
+		r.Add(r, term)
+	}
+	return r, rhos, nil
+}
+
+// bindingFactor derives participant id's binding factor for message from
+// every signing participant's published commitments, binding each
+// partial signature to this exact message and signing set.
+func bindingFactor(id int, commitments []*NonceCommitment, message []byte) (*edwards25519.Scalar, error) {
+	h := sha512.New()
+	h.Write([]byte("FROST-Ed25519-rho"))
+	fmt.Fprintf(h, ":%d:", id)
+	h.Write(message)
+	for _, c := range commitments {
+		fmt.Fprintf(h, ":%d:", c.ParticipantID)
+		h.Write(c.Hiding.Bytes())
+		h.Write(c.Binding.Bytes())
+	}
+	return edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+}
+
+// challengeScalar computes the Ed25519 challenge c = SHA512(R || A || M)
+// mod L, exactly as RFC 8032 defines it, so that the FROST-aggregated
+// (R, sum(z_i)) signature is a standard Ed25519 signature.
+func challengeScalar(r *edwards25519.Point, groupPub ed25519.PublicKey, message []byte) (*edwards25519.Scalar, error) {
+	h := sha512.New()
+	h.Write(r.Bytes())
+	h.Write(groupPub)
+	h.Write(message)
+	return edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+}
+
+// lagrangeCoefficient returns participant id's Lagrange coefficient for
+// interpolating the constant term of a polynomial at x=0, given the
+// other participants ids in the signing set.
+func lagrangeCoefficient(id int, ids []int) (*edwards25519.Scalar, error) {
+	num := scalarFromInt(1)
+	den := scalarFromInt(1)
+	for _, j := range ids {
+		if j == id {
+			continue
+		}
+		num = edwards25519.NewScalar().Multiply(num, scalarFromInt(int64(j)))
+		diff := edwards25519.NewScalar().Subtract(scalarFromInt(int64(j)), scalarFromInt(int64(id)))
+		den = edwards25519.NewScalar().Multiply(den, diff)
+	}
+	denInv := edwards25519.NewScalar().Invert(den)
+	return edwards25519.NewScalar().Multiply(num, denInv), nil
+}
+
+func evalPolynomial(coeffs []*edwards25519.Scalar, x int) *edwards25519.Scalar {
+	result := edwards25519.NewScalar()
+	xs := scalarFromInt(int64(x))
+	power := scalarFromInt(1)
+	for _, coeff := range coeffs {
+		result.Add(result, edwards25519.NewScalar().Multiply(coeff, power))
+		power = edwards25519.NewScalar().Multiply(power, xs)
+	}
+	return result
+}
+
+func scalarFromInt(n int64) *edwards25519.Scalar {
+	neg := n < 0
+	if neg {
+		n = -n
+	}
+	var buf [32]byte
+	for i := 0; n > 0; i++ {
+		buf[i] = byte(n)
+		n >>= 8
+	}
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(buf[:])
+	if err != nil {
+		panic(fmt.Sprintf("scalarFromInt: %v", err)) // buf is always far below L for the small ids/coefficients used here
+	}
+	if neg {
+		s = edwards25519.NewScalar().Subtract(edwards25519.NewScalar(), s)
+	}
+	return s
+}
+
+func randomScalar() (*edwards25519.Scalar, error) {
+	var buf [64]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return nil, err
+	}
+	return edwards25519.NewScalar().SetUniformBytes(buf[:])
+}
+
+// EncodePointHex hex-encodes a curve point, for wire/file persistence.
+func EncodePointHex(p *edwards25519.Point) string { return hex.EncodeToString(p.Bytes()) }
+
+// DecodePointHex decodes a curve point previously encoded with EncodePointHex.
+func DecodePointHex(s string) (*edwards25519.Point, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode point: %w", err)
+	}
+	p, err := new(edwards25519.Point).SetBytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("decode point: %w", err)
+	}
+	return p, nil
+}
+
+// EncodeScalarHex hex-encodes a scalar, for wire/file persistence.
+func EncodeScalarHex(s *edwards25519.Scalar) string { return hex.EncodeToString(s.Bytes()) }
+
+// DecodeScalarHex decodes a scalar previously encoded with EncodeScalarHex.
+func DecodeScalarHex(s string) (*edwards25519.Scalar, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode scalar: %w", err)
+	}
+	sc, err := new(edwards25519.Scalar).SetCanonicalBytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("decode scalar: %w", err)
+	}
+	return sc, nil
+}
+
+// keyShareFile is the on-disk JSON representation of one participant's
+// Share plus the GroupKey needed to verify against it.
+type keyShareFile struct {
+	ParticipantID      int               `json:"participant_id"`
+	Secret             string            `json:"secret"`
+	Threshold          int               `json:"threshold"`
+	GroupPublicKey     string            `json:"group_public_key"`
+	VerificationShares map[string]string `json:"verification_shares"` // participant id -> hex point
+}
+
+func persistKeyShareFile(path string, share *Share, group *GroupKey) error {
+	verif := make(map[string]string, len(group.Shares))
+	for id, p := range group.Shares {
+		verif[strconv.Itoa(id)] = EncodePointHex(p)
+	}
+	file := keyShareFile{
+		ParticipantID:      share.ParticipantID,
+		Secret:             EncodeScalarHex(share.Secret),
+		Threshold:          group.Threshold,
+		GroupPublicKey:     hex.EncodeToString(group.PublicKey),
+		VerificationShares: verif,
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode key share file: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("persist key share file: %w", err)
+	}
+	return nil
+}
+
+func decodeKeyShareFile(data []byte) (*Share, *GroupKey, error) {
+	var file keyShareFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("decode key share file: %w", err)
+	}
+	secret, err := DecodeScalarHex(file.Secret)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode share secret: %w", err)
+	}
+	pubKey, err := hex.DecodeString(file.GroupPublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode group public key: %w", err)
+	}
+
+	verif := make(map[int]*edwards25519.Point, len(file.VerificationShares))
+	for idStr, hexPoint := range file.VerificationShares {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode verification share id %q: %w", idStr, err)
+		}
+		p, err := DecodePointHex(hexPoint)
+		if err != nil {
+			return nil, nil, fmt.Errorf("decode verification share for participant %d: %w", id, err)
+		}
+		verif[id] = p
+	}
+
+	share := &Share{ParticipantID: file.ParticipantID, Secret: secret}
+	group := &GroupKey{Threshold: file.Threshold, Shares: verif, PublicKey: ed25519.PublicKey(pubKey)}
+	return share, group, nil
+}
+
+// LoadThresholdSigner loads this process's key share from path. If the
+// file does not exist, it bootstraps a degenerate 1-of-1 group (a single
+// participant who is always the full signing set) and persists it, so a
+// freshly cloned vouchd still signs out of the box. A true multi-party
+// t-of-n group is provisioned once via BootstrapThresholdGroup, with each
+// participant's share file copied into place before their instance
+// starts.
+func LoadThresholdSigner(path string) (*ThresholdSigner, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		share, group, err := decodeKeyShareFile(data)
+		if err != nil {
+			return nil, err
+		}
+		return NewThresholdSigner(share, group), nil
+	}
+
+	shares, group, err := GenerateThresholdKeys(1, 1)
+	if err != nil {
+		return nil, fmt.Errorf("bootstrap single-party group: %w", err)
+	}
+	if err := persistKeyShareFile(path, shares[0], group); err != nil {
+		return nil, err
+	}
+	return NewThresholdSigner(shares[0], group), nil
+}
+
+// LoadGroupKey loads only the public portion (no secret share) of the
+// key-share file at path, for callers — like the verify CLI — that check
+// signatures but never need to produce one.
+func LoadGroupKey(path string) (*GroupKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key share file %s: %w", path, err)
+	}
+	_, group, err := decodeKeyShareFile(data)
+	if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// BootstrapThresholdGroup runs a one-time trusted-dealer key generation
+// for an n-participant, t-threshold FROST group and writes each
+// participant's share to paths[i], so paths[i] can be copied to the
+// machine participant i will run their approver tooling from.
+func BootstrapThresholdGroup(n, t int, paths []string) (*GroupKey, error) {
+	if len(paths) != n {
+		return nil, fmt.Errorf("need exactly %d key share paths, got %d", n, len(paths))
+	}
+	shares, group, err := GenerateThresholdKeys(n, t)
+	if err != nil {
+		return nil, err
+	}
+	for i, share := range shares {
+		if err := persistKeyShareFile(paths[i], share, group); err != nil {
+			return nil, fmt.Errorf("persist share for participant %d: %w", share.ParticipantID, err)
+		}
+	}
+	return group, nil
+}
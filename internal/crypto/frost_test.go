@@ -0,0 +1,138 @@
+package crypto
+
+import (
+	"testing"
+)
+
+// signWithGroup runs a full two-round FROST ceremony using the first
+// threshold shares out of the n generated, and returns the resulting
+// aggregate signature.
+func signWithGroup(t *testing.T, shares []*Share, group *GroupKey, threshold int, message []byte) []byte {
+	t.Helper()
+
+	signers := make([]*ThresholdSigner, threshold)
+	for i := 0; i < threshold; i++ {
+		signers[i] = NewThresholdSigner(shares[i], group)
+	}
+
+	const sessionID = "test-session"
+	commitments := make([]*NonceCommitment, threshold)
+	for i, signer := range signers {
+		c, err := signer.Round1(sessionID)
+		if err != nil {
+			t.Fatalf("participant %d Round1: %v", signer.ParticipantID(), err)
+		}
+		commitments[i] = c
+	}
+
+	partials := make([]*PartialSignature, threshold)
+	for i, signer := range signers {
+		p, err := signer.Round2(sessionID, message, commitments)
+		if err != nil {
+			t.Fatalf("participant %d Round2: %v", signer.ParticipantID(), err)
+		}
+		partials[i] = p
+	}
+
+	sig, err := AggregateSignature(group, commitments, message, partials)
+	if err != nil {
+		t.Fatalf("aggregate signature: %v", err)
+	}
+	return sig
+}
+
+// TestSignAggregateVerify runs the full FROST ceremony across a handful of
+// (t, n) configurations and confirms the aggregated signature verifies
+// against the group's aggregate public key.
+func TestSignAggregateVerify(t *testing.T) {
+	cases := []struct{ n, t int }{
+		{1, 1},
+		{3, 2},
+		{5, 3},
+		{5, 5},
+	}
+
+	for _, tc := range cases {
+		shares, group, err := GenerateThresholdKeys(tc.n, tc.t)
+		if err != nil {
+			t.Fatalf("(%d,%d) generate threshold keys: %v", tc.n, tc.t, err)
+		}
+
+		message := []byte("anchor this chain head")
+		sig := signWithGroup(t, shares, group, tc.t, message)
+
+		if !group.Verify(message, sig) {
+			t.Fatalf("(%d,%d) aggregated signature failed to verify", tc.n, tc.t)
+		}
+		if group.Verify([]byte("a different message"), sig) {
+			t.Fatalf("(%d,%d) signature verified against the wrong message", tc.n, tc.t)
+		}
+	}
+}
+
+// TestSignAggregateVerify_DifferentSigningSubsets confirms that two
+// different size-t subsets of the same n-participant group both produce
+// signatures that verify against the single group public key, since
+// Lagrange interpolation must correctly reconstruct the same secret
+// regardless of which t participants contributed.
+func TestSignAggregateVerify_DifferentSigningSubsets(t *testing.T) {
+	shares, group, err := GenerateThresholdKeys(5, 3)
+	if err != nil {
+		t.Fatalf("generate threshold keys: %v", err)
+	}
+	message := []byte("anchor this chain head")
+
+	sigA := signWithGroup(t, shares[:3], group, 3, message)
+	if !group.Verify(message, sigA) {
+		t.Fatal("subset {1,2,3} signature failed to verify")
+	}
+
+	sigB := signWithGroup(t, shares[2:], group, 3, message)
+	if !group.Verify(message, sigB) {
+		t.Fatal("subset {3,4,5} signature failed to verify")
+	}
+}
+
+// TestRefreshShares_RejectsShareNotMatchingVerificationShare confirms that
+// RefreshShares checks each supplied share against the group's recorded
+// verification share before trusting it, rather than accepting whatever
+// secret a caller names a participant ID against.
+func TestRefreshShares_RejectsShareNotMatchingVerificationShare(t *testing.T) {
+	shares, group, err := GenerateThresholdKeys(3, 2)
+	if err != nil {
+		t.Fatalf("generate threshold keys: %v", err)
+	}
+
+	forged := make([]*Share, len(shares))
+	copy(forged, shares)
+	fabricated, _, err := GenerateThresholdKeys(3, 2)
+	if err != nil {
+		t.Fatalf("generate fabricated keys: %v", err)
+	}
+	forged[0] = &Share{ParticipantID: shares[0].ParticipantID, Secret: fabricated[0].Secret}
+
+	if _, _, err := RefreshShares(forged, group); err == nil {
+		t.Fatal("expected RefreshShares to reject a share that doesn't match its recorded verification share, got nil error")
+	}
+
+	if _, _, err := RefreshShares(shares, group); err != nil {
+		t.Fatalf("RefreshShares rejected genuine shares: %v", err)
+	}
+}
+
+// TestRefreshShares_RejectsDuplicateParticipant confirms that padding the
+// share list with repeats of one real participant's share (to satisfy the
+// "len(shares) == len(group.Shares)" count check without actually
+// supplying every participant's share) is rejected, rather than silently
+// dropping the unsupplied participants from the refreshed group.
+func TestRefreshShares_RejectsDuplicateParticipant(t *testing.T) {
+	shares, group, err := GenerateThresholdKeys(3, 2)
+	if err != nil {
+		t.Fatalf("generate threshold keys: %v", err)
+	}
+
+	padded := []*Share{shares[0], shares[0], shares[0]}
+	if _, _, err := RefreshShares(padded, group); err == nil {
+		t.Fatal("expected RefreshShares to reject a share list with a duplicated participant, got nil error")
+	}
+}
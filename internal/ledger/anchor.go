@@ -0,0 +1,126 @@
+package ledger
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/vouch/internal/ledger/bitcoin"
+	"github.com/yourname/vouch/internal/ledger/merkletree"
+	"github.com/yourname/vouch/internal/ledger/store"
+)
+
+// anchorLoop periodically (and on demand via flushNow, see RequestFlush)
+// batches the chain heads accumulated since the last anchor into a single
+// Merkle tree and publishes its root as one Bitcoin OP_RETURN commitment.
+func (w *Worker) anchorLoop() {
+	ticker := time.NewTicker(w.anchorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := FlushAnchor(w.db, w.runID); err != nil {
+				log.Printf("[ANCHOR] %v", err)
+			}
+		case <-w.flushNow:
+			if err := FlushAnchor(w.db, w.runID); err != nil {
+				log.Printf("[ANCHOR] %v", err)
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// RequestFlush asks the worker's anchor loop to run immediately instead of
+// waiting for --anchor-interval to elapse. `vouch anchor` does not use
+// it; that command calls FlushAnchor directly.
+func (w *Worker) RequestFlush() {
+	select {
+	case w.flushNow <- struct{}{}:
+	default:
+	}
+}
+
+// FlushAnchor batches every event for runID that has no inclusion proof
+// yet into a single Merkle tree and publishes its root as one Bitcoin
+// OP_RETURN commitment, storing a proof per covered event. It is the
+// single code path used both by the worker's periodic anchor loop and by
+// the standalone `vouch anchor` command, so it reads its batch from the
+// database rather than in-process state.
+func FlushAnchor(db *store.DB, runID string) error {
+	pending, err := db.GetUnanchoredEvents(runID)
+	if err != nil {
+		return fmt.Errorf("load unanchored events: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	leaves := make([][]byte, len(pending))
+	for i, e := range pending {
+		hashBytes, err := hex.DecodeString(e.Hash)
+		if err != nil {
+			return fmt.Errorf("malformed chain head %s: %w", e.ID, err)
+		}
+		leaves[i] = merkletree.Sha256d(hashBytes)
+	}
+
+	tree := merkletree.New(leaves)
+	root := tree.Root()
+	rootHex := hex.EncodeToString(root)
+
+	txID, blockHeight, err := bitcoin.PublishOpReturn(root)
+	if err != nil {
+		return fmt.Errorf("publish commitment for %d events: %w", len(pending), err)
+	}
+
+	anchorID := uuid.New().String()
+	if err := db.SaveAnchor(store.Anchor{
+		ID:          anchorID,
+		RunID:       runID,
+		MerkleRoot:  rootHex,
+		TxID:        txID,
+		BlockHeight: blockHeight,
+		CreatedAt:   time.Now(),
+	}); err != nil {
+		return fmt.Errorf("save anchor record: %w", err)
+	}
+
+	for i, e := range pending {
+		siblings := tree.ProofFor(i)
+		if err := db.SaveInclusionProof(store.InclusionProof{
+			EventID:     e.ID,
+			AnchorID:    anchorID,
+			LeafHash:    hex.EncodeToString(leaves[i]),
+			TxID:        txID,
+			BlockHeight: blockHeight,
+		}, encodeSiblings(siblings)); err != nil {
+			log.Printf("[ANCHOR] failed to save inclusion proof for %s: %v", e.ID, err)
+		}
+	}
+
+	log.Printf("[ANCHOR] committed %d events under root %s in tx %s (block %d)", len(pending), rootHex, txID, blockHeight)
+	return nil
+}
+
+// encodeSiblings serializes a sibling path as "<hex>:<L|R>,..." for storage
+// alongside the inclusion proof; audit.decodeSiblings mirrors this format.
+func encodeSiblings(siblings []merkletree.Sibling) string {
+	out := make([]byte, 0, len(siblings)*68)
+	for i, s := range siblings {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		side := byte('R')
+		if s.Left {
+			side = 'L'
+		}
+		out = append(out, []byte(hex.EncodeToString(s.Hash))...)
+		out = append(out, ':', side)
+	}
+	return string(out)
+}
@@ -0,0 +1,182 @@
+// Package audit verifies a run's hash chain and its Bitcoin anchors,
+// re-deriving every commitment from stored data rather than trusting the
+// ledger's own bookkeeping. The chain check runs offline; the anchor check
+// fetches each anchoring tx from Blockstream.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/yourname/vouch/internal/crypto"
+	"github.com/yourname/vouch/internal/ledger/bitcoin"
+	"github.com/yourname/vouch/internal/ledger/merkletree"
+	"github.com/yourname/vouch/internal/ledger/store"
+)
+
+// Result is the outcome of VerifyChain.
+type Result struct {
+	Valid        bool
+	TotalEvents  int
+	FailedAtSeq  int64
+	ErrorMessage string
+}
+
+// AnchorResult is the outcome of VerifyAnchors.
+type AnchorResult struct {
+	Valid          bool
+	AnchorsChecked int
+	ErrorMessage   string
+}
+
+// VerifyChain recomputes every event's hash and signature in sequence and
+// confirms each links to the previous event's hash, catching any
+// tampering, reordering, or forged signature. Ordinary events are signed
+// over their chain hash; "blocked" events are co-signed by a FROST
+// threshold of approvers over store.ApprovalDigest instead, since that
+// ceremony runs before the event claims a chain slot (see
+// ledger.Worker.SubmitSigned) — either way, group.Verify checks the
+// result the same way, since a FROST-aggregated signature is a standard
+// Ed25519 signature.
+func VerifyChain(backend store.Backend, runID string, group *crypto.GroupKey) (*Result, error) {
+	db := store.FromBackend(backend)
+	events, err := db.GetRecentEvents(runID, 1<<30)
+	if err != nil {
+		return nil, fmt.Errorf("load events: %w", err)
+	}
+	// GetRecentEvents returns oldest first, which is the replay order.
+
+	prevHash := ""
+	for _, e := range events {
+		if prevHash != "" && e.PrevHash != prevHash {
+			return &Result{Valid: false, TotalEvents: len(events), FailedAtSeq: e.SeqIndex,
+				ErrorMessage: fmt.Sprintf("event %s prev_hash does not match chain head", e.ID)}, nil
+		}
+
+		h := sha256.New()
+		h.Write([]byte(e.PrevHash))
+		h.Write([]byte(e.ID))
+		h.Write([]byte(e.EventType))
+		h.Write([]byte(e.Method))
+		wantHash := hex.EncodeToString(h.Sum(nil))
+		if wantHash != e.Hash {
+			return &Result{Valid: false, TotalEvents: len(events), FailedAtSeq: e.SeqIndex,
+				ErrorMessage: fmt.Sprintf("event %s hash does not match its recorded contents", e.ID)}, nil
+		}
+
+		sig, err := hex.DecodeString(e.Signature)
+		if err != nil {
+			return &Result{Valid: false, TotalEvents: len(events), FailedAtSeq: e.SeqIndex,
+				ErrorMessage: fmt.Sprintf("event %s has malformed signature", e.ID)}, nil
+		}
+		signedMessage := []byte(e.Hash)
+		if e.WasBlocked {
+			signedMessage = store.ApprovalDigest(e.ID, e.EventType, e.Method, e.TaskID, e.PolicyID, e.Params)
+		}
+		if !group.Verify(signedMessage, sig) {
+			return &Result{Valid: false, TotalEvents: len(events), FailedAtSeq: e.SeqIndex,
+				ErrorMessage: fmt.Sprintf("event %s signature does not verify", e.ID)}, nil
+		}
+
+		prevHash = e.Hash
+	}
+
+	return &Result{Valid: true, TotalEvents: len(events)}, nil
+}
+
+// VerifyAnchors checks every Bitcoin anchor recorded for runID: for each
+// anchor it (1) fetches the raw OP_RETURN and the tx's confirmation status
+// via Blockstream, (2) confirms the payload equals the recorded Merkle
+// root, (3) where a block height was recorded, confirms the tx is
+// confirmed at that height, and (4) for every covered event, folds its
+// stored sibling hashes with its leaf hash offline and confirms the result
+// equals that root. An anchor recorded with block height 0 (the height
+// PublishOpReturn returns) is not checked for confirmation, so a tx still
+// unconfirmed passes.
+func VerifyAnchors(backend store.Backend, runID string) (*AnchorResult, error) {
+	db := store.FromBackend(backend)
+	anchors, err := db.GetAnchors(runID)
+	if err != nil {
+		return nil, fmt.Errorf("load anchors: %w", err)
+	}
+	if len(anchors) == 0 {
+		return &AnchorResult{Valid: true, AnchorsChecked: 0}, nil
+	}
+
+	for _, a := range anchors {
+		payload, confirmedHeight, err := bitcoin.FetchOpReturnPayload(a.TxID)
+		if err != nil {
+			return &AnchorResult{Valid: false, AnchorsChecked: len(anchors),
+				ErrorMessage: fmt.Sprintf("anchor %s: %v", a.ID, err)}, nil
+		}
+
+		if hex.EncodeToString(payload) != a.MerkleRoot {
+			return &AnchorResult{Valid: false, AnchorsChecked: len(anchors),
+				ErrorMessage: fmt.Sprintf("anchor %s: on-chain OP_RETURN does not match recorded Merkle root", a.ID)}, nil
+		}
+
+		if a.BlockHeight != 0 && confirmedHeight != a.BlockHeight {
+			return &AnchorResult{Valid: false, AnchorsChecked: len(anchors),
+				ErrorMessage: fmt.Sprintf("anchor %s: tx %s not confirmed at recorded block %d", a.ID, a.TxID, a.BlockHeight)}, nil
+		}
+
+		for _, eventID := range a.CoveredEvents {
+			if err := verifyInclusion(db, eventID, a.MerkleRoot); err != nil {
+				return &AnchorResult{Valid: false, AnchorsChecked: len(anchors),
+					ErrorMessage: fmt.Sprintf("anchor %s: %v", a.ID, err)}, nil
+			}
+		}
+	}
+
+	return &AnchorResult{Valid: true, AnchorsChecked: len(anchors)}, nil
+}
+
+// verifyInclusion confirms that eventID's leaf hash folds, via its stored
+// sibling path, up to wantRoot — the Merkle root committed by the anchor
+// that covers it.
+func verifyInclusion(db *store.DB, eventID, wantRoot string) error {
+	proof, encodedSiblings, err := db.GetInclusionProof(eventID)
+	if err != nil {
+		return fmt.Errorf("load inclusion proof for %s: %w", eventID, err)
+	}
+
+	leaf, err := hex.DecodeString(proof.LeafHash)
+	if err != nil {
+		return fmt.Errorf("malformed leaf hash for %s: %w", eventID, err)
+	}
+
+	siblings, err := decodeSiblings(encodedSiblings)
+	if err != nil {
+		return fmt.Errorf("malformed sibling path for %s: %w", eventID, err)
+	}
+
+	root := hex.EncodeToString(merkletree.FoldProof(leaf, siblings))
+	if root != wantRoot {
+		return fmt.Errorf("event %s inclusion proof folds to %s, want %s", eventID, root, wantRoot)
+	}
+	return nil
+}
+
+// decodeSiblings parses the "<hex>:<L|R>,..." format written by
+// ledger.encodeSiblings.
+func decodeSiblings(encoded string) ([]merkletree.Sibling, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	parts := strings.Split(encoded, ",")
+	siblings := make([]merkletree.Sibling, 0, len(parts))
+	for _, part := range parts {
+		fields := strings.SplitN(part, ":", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed sibling entry %q", part)
+		}
+		hash, err := hex.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("malformed sibling hash %q: %w", fields[0], err)
+		}
+		siblings = append(siblings, merkletree.Sibling{Hash: hash, Left: fields[1] == "L"})
+	}
+	return siblings, nil
+}
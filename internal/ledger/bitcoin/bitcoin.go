@@ -0,0 +1,291 @@
+// Package bitcoin is the thin client the ledger worker and audit package
+// share for publishing and reading back OP_RETURN anchor commitments.
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+const blockstreamBaseURL = "https://blockstream.info/api"
+
+// defaultFeeRateSatsPerVByte is used unless VOUCH_ANCHOR_FEE_RATE overrides it.
+const defaultFeeRateSatsPerVByte = 10
+
+// dustLimitSats is the smallest change output buildOpReturnTx will
+// create; a smaller leftover is folded into the fee instead.
+const dustLimitSats = 546
+
+// PublishOpReturn broadcasts a single OP_RETURN transaction carrying root
+// as its payload and returns the resulting txid. Confirmation (and thus
+// block height) is not immediate and is not waited for here, so the
+// returned block height is always 0.
+func PublishOpReturn(root []byte) (txID string, blockHeight int64, err error) {
+	rawTx, err := buildOpReturnTx(root)
+	if err != nil {
+		return "", 0, fmt.Errorf("build op_return tx: %w", err)
+	}
+
+	resp, err := http.Post(blockstreamBaseURL+"/tx", "text/plain", bytes.NewReader(rawTx))
+	if err != nil {
+		return "", 0, fmt.Errorf("broadcast tx: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("broadcast rejected: status %d", resp.StatusCode)
+	}
+
+	var id bytes.Buffer
+	if _, err := id.ReadFrom(resp.Body); err != nil {
+		return "", 0, fmt.Errorf("read txid: %w", err)
+	}
+
+	// Unconfirmed until mined; VerifyAnchors reads the status from /tx/:txid later.
+	return id.String(), 0, nil
+}
+
+// anchorWallet is the single P2PKH keypair buildOpReturnTx spends from,
+// loaded from the WIF in VOUCH_ANCHOR_WALLET.
+type anchorWallet struct {
+	wif      *btcutil.WIF
+	addr     *btcutil.AddressPubKeyHash
+	pkScript []byte
+}
+
+func loadAnchorWallet() (*anchorWallet, error) {
+	wifStr := os.Getenv("VOUCH_ANCHOR_WALLET")
+	if wifStr == "" {
+		return nil, fmt.Errorf("VOUCH_ANCHOR_WALLET not set: export a WIF-encoded private key for the anchoring wallet")
+	}
+	wif, err := btcutil.DecodeWIF(wifStr)
+	if err != nil {
+		return nil, fmt.Errorf("decode VOUCH_ANCHOR_WALLET as WIF: %w", err)
+	}
+
+	pkHash := btcutil.Hash160(wif.SerializePubKey())
+	addr, err := btcutil.NewAddressPubKeyHash(pkHash, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, fmt.Errorf("derive anchoring address: %w", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, fmt.Errorf("build pkScript for anchoring address: %w", err)
+	}
+	return &anchorWallet{wif: wif, addr: addr, pkScript: pkScript}, nil
+}
+
+// utxo is one confirmed, spendable output of the anchoring wallet, as
+// reported by Blockstream's /address/:address/utxo.
+type utxo struct {
+	txid  string
+	vout  uint32
+	value int64
+}
+
+func fetchUTXOs(address string) ([]utxo, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(fmt.Sprintf("%s/address/%s/utxo", blockstreamBaseURL, address))
+	if err != nil {
+		return nil, fmt.Errorf("fetch utxos: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch utxos: status %d", resp.StatusCode)
+	}
+
+	var raw []struct {
+		TxID   string `json:"txid"`
+		Vout   uint32 `json:"vout"`
+		Value  int64  `json:"value"`
+		Status struct {
+			Confirmed bool `json:"confirmed"`
+		} `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode utxos: %w", err)
+	}
+
+	out := make([]utxo, 0, len(raw))
+	for _, u := range raw {
+		if !u.Status.Confirmed {
+			continue
+		}
+		out = append(out, utxo{txid: u.TxID, vout: u.Vout, value: u.Value})
+	}
+	return out, nil
+}
+
+// feeRateSatsPerVByte is a fixed-rate fee estimate, overridable for
+// deployments that need to react to mempool conditions.
+func feeRateSatsPerVByte() int64 {
+	if v := os.Getenv("VOUCH_ANCHOR_FEE_RATE"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultFeeRateSatsPerVByte
+}
+
+// buildOpReturnTx constructs and signs a raw transaction whose sole data
+// output is `OP_RETURN root`, spending confirmed UTXOs from the wallet
+// configured via VOUCH_ANCHOR_WALLET and returning any leftover change
+// back to that same address. The result is hex-encoded, ready to POST as
+// Blockstream's /tx broadcast endpoint expects.
+func buildOpReturnTx(root []byte) ([]byte, error) {
+	wallet, err := loadAnchorWallet()
+	if err != nil {
+		return nil, err
+	}
+
+	address := wallet.addr.EncodeAddress()
+	utxos, err := fetchUTXOs(address)
+	if err != nil {
+		return nil, fmt.Errorf("fetch utxos for %s: %w", address, err)
+	}
+	if len(utxos) == 0 {
+		return nil, fmt.Errorf("anchoring wallet %s has no spendable (confirmed) UTXOs", address)
+	}
+
+	opReturnScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_RETURN).AddData(root).Script()
+	if err != nil {
+		return nil, fmt.Errorf("build OP_RETURN script: %w", err)
+	}
+
+	// Coin-select greedily, re-estimating the P2PKH-input-dominated
+	// transaction size (~148 vbytes/input) as inputs are added, until
+	// the running total covers the fee at the current input count.
+	const baseOverheadVBytes = 11
+	const changeOutVBytes = 34
+	const p2pkhInputVBytes = 148
+	opReturnOutVBytes := len(opReturnScript) + 9
+
+	estSize := func(nInputs int) int64 {
+		return int64(baseOverheadVBytes + opReturnOutVBytes + changeOutVBytes + nInputs*p2pkhInputVBytes)
+	}
+
+	var selected []utxo
+	var total int64
+	for _, u := range utxos {
+		selected = append(selected, u)
+		total += u.value
+		if total >= estSize(len(selected))*feeRateSatsPerVByte() {
+			break
+		}
+	}
+	fee := estSize(len(selected)) * feeRateSatsPerVByte()
+	if total < fee {
+		return nil, fmt.Errorf("anchoring wallet %s has insufficient confirmed balance (%d sats) to cover an estimated %d sat fee", address, total, fee)
+	}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxOut(wire.NewTxOut(0, opReturnScript))
+
+	for _, u := range selected {
+		hash, err := chainhash.NewHashFromStr(u.txid)
+		if err != nil {
+			return nil, fmt.Errorf("parse utxo txid %s: %w", u.txid, err)
+		}
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(hash, u.vout), nil, nil))
+	}
+
+	if change := total - fee; change >= dustLimitSats {
+		tx.AddTxOut(wire.NewTxOut(change, wallet.pkScript))
+	}
+	// A leftover below dustLimitSats is simply absorbed into the fee.
+
+	for i := range tx.TxIn {
+		sigScript, err := txscript.SignatureScript(tx, i, wallet.pkScript, txscript.SigHashAll, wallet.wif.PrivKey, wallet.wif.CompressPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("sign input %d: %w", i, err)
+		}
+		tx.TxIn[i].SignatureScript = sigScript
+	}
+
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return nil, fmt.Errorf("serialize tx: %w", err)
+	}
+	return []byte(hex.EncodeToString(buf.Bytes())), nil
+}
+
+// FetchOpReturnPayload retrieves the OP_RETURN payload and confirmation
+// status for txID from Blockstream, for use by audit.VerifyAnchors.
+func FetchOpReturnPayload(txID string) (payload []byte, confirmedHeight int64, err error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	txResp, err := client.Get(fmt.Sprintf("%s/tx/%s", blockstreamBaseURL, txID))
+	if err != nil {
+		return nil, 0, fmt.Errorf("fetch tx: %w", err)
+	}
+	defer txResp.Body.Close()
+
+	var tx struct {
+		Vout []struct {
+			ScriptPubKey struct {
+				Type string `json:"type"`
+				Hex  string `json:"hex"`
+			} `json:"scriptpubkey"`
+		} `json:"vout"`
+		Status struct {
+			Confirmed   bool  `json:"confirmed"`
+			BlockHeight int64 `json:"block_height"`
+		} `json:"status"`
+	}
+	if err := json.NewDecoder(txResp.Body).Decode(&tx); err != nil {
+		return nil, 0, fmt.Errorf("decode tx: %w", err)
+	}
+
+	for _, out := range tx.Vout {
+		if out.ScriptPubKey.Type == "op_return" {
+			payload, err = opReturnPayloadFromScript(out.ScriptPubKey.Hex)
+			if err != nil {
+				return nil, 0, err
+			}
+			break
+		}
+	}
+	if payload == nil {
+		return nil, 0, fmt.Errorf("tx %s has no OP_RETURN output", txID)
+	}
+
+	if !tx.Status.Confirmed {
+		return payload, 0, nil
+	}
+	return payload, tx.Status.BlockHeight, nil
+}
+
+// opReturnPayloadFromScript extracts the pushed data from a
+// `OP_RETURN <push>` scriptPubKey, given as hex: one opcode byte (0x6a)
+// followed by a standard script data push.
+func opReturnPayloadFromScript(scriptHex string) ([]byte, error) {
+	script, err := hex.DecodeString(scriptHex)
+	if err != nil {
+		return nil, fmt.Errorf("decode scriptPubKey: %w", err)
+	}
+	if len(script) < 2 || script[0] != 0x6a {
+		return nil, fmt.Errorf("scriptPubKey does not start with OP_RETURN")
+	}
+
+	pushLen := int(script[1])
+	rest := script[2:]
+	if pushLen > 0 && pushLen <= 75 {
+		if len(rest) < pushLen {
+			return nil, fmt.Errorf("scriptPubKey push length exceeds script")
+		}
+		return rest[:pushLen], nil
+	}
+	return nil, fmt.Errorf("unsupported OP_RETURN push encoding (len byte 0x%x)", script[1])
+}
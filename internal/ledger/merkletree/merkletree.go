@@ -0,0 +1,97 @@
+// Package merkletree builds and verifies the Merkle trees used to batch
+// many ledger chain heads into a single Bitcoin OP_RETURN commitment. It
+// follows Bitcoin's own conventions (SHA-256d, odd-node duplication) so
+// that anchoring and verification agree bit-for-bit with how the
+// commitment was built.
+package merkletree
+
+import "crypto/sha256"
+
+// Sha256d is Bitcoin's double SHA-256.
+func Sha256d(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
+
+// Sibling is one step of an inclusion proof: the hash to fold in next and
+// which side of the pair it sits on.
+type Sibling struct {
+	Hash []byte
+	Left bool // true if Hash is the left sibling, i.e. leaf is on the right
+}
+
+// Tree is a Bitcoin-style Merkle tree: each level is built by hashing pairs
+// of the level below, duplicating the last node when a level has an odd
+// count (the same rule used for Bitcoin's transaction Merkle root).
+type Tree struct {
+	levels [][][]byte // levels[0] is the leaves
+}
+
+// New builds a Merkle tree over leaves, which must be pre-hashed (already
+// passed through Sha256d) and non-empty.
+func New(leaves [][]byte) *Tree {
+	level := make([][]byte, len(leaves))
+	copy(level, leaves)
+
+	levels := [][][]byte{level}
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			left := level[i]
+			right := left
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, Sha256d(append(append([]byte{}, left...), right...)))
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return &Tree{levels: levels}
+}
+
+// Root returns the Merkle root, or nil if the tree has no leaves.
+func (t *Tree) Root() []byte {
+	top := t.levels[len(t.levels)-1]
+	if len(top) == 0 {
+		return nil
+	}
+	return top[0]
+}
+
+// ProofFor returns the sibling path needed to fold leaf index up to the
+// root, in bottom-up order.
+func (t *Tree) ProofFor(index int) []Sibling {
+	var proof []Sibling
+	idx := index
+	for _, level := range t.levels[:len(t.levels)-1] {
+		isRight := idx%2 == 1
+		siblingIdx := idx - 1
+		if !isRight {
+			siblingIdx = idx + 1
+		}
+		if siblingIdx >= len(level) {
+			siblingIdx = idx // odd node: duplicated with itself
+		}
+		proof = append(proof, Sibling{Hash: level[siblingIdx], Left: isRight})
+		idx /= 2
+	}
+	return proof
+}
+
+// FoldProof recomputes the Merkle root by folding leaf with each sibling in
+// order, exactly mirroring how New built the tree. This is what offline
+// verification runs: given a leaf hash and its stored proof, confirm the
+// result equals the root committed on-chain.
+func FoldProof(leaf []byte, proof []Sibling) []byte {
+	cur := leaf
+	for _, s := range proof {
+		if s.Left {
+			cur = Sha256d(append(append([]byte{}, s.Hash...), cur...))
+		} else {
+			cur = Sha256d(append(append([]byte{}, cur...), s.Hash...))
+		}
+	}
+	return cur
+}
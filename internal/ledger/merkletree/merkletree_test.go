@@ -0,0 +1,46 @@
+package merkletree
+
+import (
+	"bytes"
+	"testing"
+)
+
+func leavesOf(n int) [][]byte {
+	leaves := make([][]byte, n)
+	for i := range leaves {
+		leaves[i] = Sha256d([]byte{byte(i)})
+	}
+	return leaves
+}
+
+// TestProofRoundTrip confirms FoldProof reconstructs the tree's root for
+// every leaf, across both even and odd leaf counts (odd counts exercise
+// Bitcoin's duplicate-the-last-node rule).
+func TestProofRoundTrip(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5, 7, 8} {
+		leaves := leavesOf(n)
+		tree := New(leaves)
+		root := tree.Root()
+
+		for i := range leaves {
+			proof := tree.ProofFor(i)
+			got := FoldProof(leaves[i], proof)
+			if !bytes.Equal(got, root) {
+				t.Fatalf("n=%d leaf=%d: folded root %x, want %x", n, i, got, root)
+			}
+		}
+	}
+}
+
+// TestProofRejectsWrongLeaf confirms a proof doesn't fold to the tree's
+// root when given a leaf hash it wasn't issued for.
+func TestProofRejectsWrongLeaf(t *testing.T) {
+	leaves := leavesOf(5)
+	tree := New(leaves)
+	proof := tree.ProofFor(2)
+
+	wrongLeaf := Sha256d([]byte("not a member of this tree"))
+	if got := FoldProof(wrongLeaf, proof); bytes.Equal(got, tree.Root()) {
+		t.Fatal("FoldProof folded an unrelated leaf to the real root")
+	}
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ObjectMeta is the optimistic-concurrency metadata a Backend attaches to
+// every stored value, mirroring etcd's mod-revision.
+type ObjectMeta struct {
+	ModRevision int64
+	TTL         time.Duration
+}
+
+// WatchEvent is one change delivered by Backend.Watch.
+type WatchEvent struct {
+	Key         string
+	Value       []byte
+	ModRevision int64
+	Deleted     bool
+}
+
+// TryUpdateFunc computes the next value to write given the value and
+// metadata currently stored at a key. Returning a non-nil error aborts the
+// update without writing anything.
+type TryUpdateFunc func(oldValue []byte, meta ObjectMeta) (newValue []byte, ttl time.Duration, err error)
+
+// Backend is the storage interface every ledger driver implements, so the
+// worker and audit code run unmodified whether the chain lives in SQLite,
+// etcd, or Postgres.
+type Backend interface {
+	// Get fetches the raw value stored at key. found is false if the key
+	// does not exist.
+	Get(ctx context.Context, key string) (value []byte, meta ObjectMeta, found bool, err error)
+
+	// List returns every value stored under keyPrefix, keyed by their full
+	// key, so callers can sort lexicographically (e.g. zero-padded
+	// sequence numbers) to recover insertion order.
+	List(ctx context.Context, keyPrefix string) (map[string][]byte, error)
+
+	// CompareAndSwap writes newValue at key only if the stored
+	// mod-revision still equals expectedModRevision (0 means "key must
+	// not exist yet"). It returns ErrConflict if the compare fails.
+	CompareAndSwap(ctx context.Context, key string, expectedModRevision int64, newValue []byte) error
+
+	// GuaranteedUpdate runs the kube-apiserver GuaranteedUpdate pattern:
+	// it reads the current value at key, calls tryUpdate, and attempts a
+	// CompareAndSwap of the result. On a lost race it re-reads the key to
+	// pick up its new mod-revision and retries; if mustCheckData is true it
+	// also re-invokes tryUpdate with the fresh value before retrying,
+	// otherwise it retries with the value tryUpdate already returned (safe
+	// only when tryUpdate is independent of oldValue). It returns the value
+	// that was ultimately committed.
+	GuaranteedUpdate(ctx context.Context, key string, mustCheckData bool, tryUpdate TryUpdateFunc) ([]byte, error)
+
+	// Watch streams subsequent writes to key until ctx is canceled or the
+	// backend closes the channel.
+	Watch(ctx context.Context, key string) (<-chan WatchEvent, error)
+
+	Close() error
+}
+
+// ErrConflict is returned by CompareAndSwap when expectedModRevision does
+// not match the stored value.
+var ErrConflict = fmt.Errorf("store: compare-and-swap conflict")
+
+// RunGuaranteedUpdate implements the retry loop described by Backend.GuaranteedUpdate
+// against a pair of primitive get/cas functions, so every driver can share
+// one implementation of the pattern instead of reimplementing the retry
+// logic.
+func RunGuaranteedUpdate(
+	ctx context.Context,
+	get func(ctx context.Context) ([]byte, ObjectMeta, bool, error),
+	cas func(ctx context.Context, expectedModRevision int64, newValue []byte) error,
+	mustCheckData bool,
+	tryUpdate TryUpdateFunc,
+) ([]byte, error) {
+	value, meta, _, err := get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("guaranteed update: initial read: %w", err)
+	}
+
+	newValue, _, err := tryUpdate(value, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if err := cas(ctx, meta.ModRevision, newValue); err != nil {
+			if err != ErrConflict {
+				return nil, fmt.Errorf("guaranteed update: compare-and-swap: %w", err)
+			}
+
+			value, meta, _, err = get(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("guaranteed update: re-read after conflict: %w", err)
+			}
+			if mustCheckData {
+				// The value changed under us and tryUpdate's result may
+				// depend on it, so recompute before retrying the CAS.
+				newValue, _, err = tryUpdate(value, meta)
+				if err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		return newValue, nil
+	}
+}
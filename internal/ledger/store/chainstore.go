@@ -0,0 +1,366 @@
+// Package store is the persistence layer for the ledger. It is split into
+// a generic, etcd3-style Backend interface (backend.go; this file's DB
+// sits on top of it) and the concrete drivers in sqlite.go, etcd.go, and
+// postgres.go.
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DB is the ledger's domain-level handle: it turns the generic Get/List/
+// GuaranteedUpdate/CompareAndSwap primitives of a Backend into the
+// run, event, anchor, and inclusion-proof operations the worker and audit
+// packages need, so none of them have to know which Backend they're
+// talking to.
+type DB struct {
+	Backend
+}
+
+// FromBackend wraps an already-constructed Backend (e.g. one handed to
+// NewWorker or audit.VerifyChain) in the domain-level DB type.
+func FromBackend(b Backend) *DB {
+	if db, ok := b.(*DB); ok {
+		return db
+	}
+	return &DB{Backend: b}
+}
+
+// NewDB is a thin factory that opens the Backend named by dsn's URL
+// scheme: "sqlite://path", "etcd://host:port,..." or "postgres://...". A
+// bare path with no scheme is treated as sqlite for backward compatibility.
+func NewDB(dsn string) (*DB, error) {
+	scheme, rest := splitScheme(dsn)
+	var backend Backend
+	var err error
+
+	switch scheme {
+	case "", "sqlite":
+		backend, err = NewSQLiteBackend(rest)
+	case "etcd":
+		backend, err = NewEtcdBackend(rest)
+	case "postgres", "postgresql":
+		backend, err = NewPostgresBackend(dsn)
+	default:
+		return nil, fmt.Errorf("unknown store scheme %q", scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &DB{Backend: backend}, nil
+}
+
+func splitScheme(dsn string) (scheme, rest string) {
+	i := strings.Index(dsn, "://")
+	if i < 0 {
+		return "", dsn
+	}
+	return dsn[:i], dsn[i+3:]
+}
+
+func runKey(runID string) string               { return "run:" + runID }
+func headKey(runID string) string              { return "head:" + runID }
+func eventKey(runID, seq string) string        { return "event:" + runID + ":" + seq }
+func eventPrefix(runID string) string          { return "event:" + runID + ":" }
+func anchorKey(runID, anchorID string) string  { return "anchor:" + runID + ":" + anchorID }
+func anchorPrefix(runID string) string         { return "anchor:" + runID + ":" }
+func proofKey(eventID string) string           { return "proof:" + eventID }
+
+const currentRunKey = "current-run"
+
+func seqKey(seq int64) string { return fmt.Sprintf("%020d", seq) }
+
+// runRecord is the JSON value stored at runKey.
+type runRecord struct {
+	AgentName   string    `json:"agent_name"`
+	GenesisHash string    `json:"genesis_hash"`
+	PubKey      string    `json:"pub_key"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// chainHead is the JSON value stored at headKey, the single key every
+// vouchd instance races to CompareAndSwap when admitting a new event.
+type chainHead struct {
+	NextSeq  int64  `json:"next_seq"`
+	LastHash string `json:"last_hash"`
+}
+
+// SaveRun records a newly created run's metadata and makes it current.
+func (db *DB) SaveRun(runID, agentName, genesisHash, pubKey string, createdAt time.Time) error {
+	ctx := context.Background()
+	rec := runRecord{AgentName: agentName, GenesisHash: genesisHash, PubKey: pubKey, CreatedAt: createdAt}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := db.CompareAndSwap(ctx, runKey(runID), 0, data); err != nil {
+		return fmt.Errorf("save run: %w", err)
+	}
+	// currentRunKey may or may not already exist from a prior run, so set
+	// it via GuaranteedUpdate rather than assuming either state.
+	if _, err := db.GuaranteedUpdate(ctx, currentRunKey, false, func([]byte, ObjectMeta) ([]byte, time.Duration, error) {
+		return []byte(runID), 0, nil
+	}); err != nil {
+		return fmt.Errorf("save run: set current run: %w", err)
+	}
+	return nil
+}
+
+// GetRunID returns the most recently created run, or "" if none exists.
+func (db *DB) GetRunID() (string, error) {
+	value, _, found, err := db.Get(context.Background(), currentRunKey)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", nil
+	}
+	return string(value), nil
+}
+
+// GetRunInfo returns the agent name, genesis hash, and public key recorded
+// for runID.
+func (db *DB) GetRunInfo(runID string) (agentName, genesisHash, pubKey string, err error) {
+	value, _, found, err := db.Get(context.Background(), runKey(runID))
+	if err != nil {
+		return "", "", "", err
+	}
+	if !found {
+		return "", "", "", fmt.Errorf("run %s not found", runID)
+	}
+	var rec runRecord
+	if err := json.Unmarshal(value, &rec); err != nil {
+		return "", "", "", err
+	}
+	return rec.AgentName, rec.GenesisHash, rec.PubKey, nil
+}
+
+// SeedChainHead initializes a new run's chain head to genesisHash so the
+// first admitted event folds against a real previous hash instead of an
+// empty string.
+func (db *DB) SeedChainHead(runID, genesisHash string) error {
+	head := chainHead{NextSeq: 0, LastHash: genesisHash}
+	data, err := json.Marshal(head)
+	if err != nil {
+		return err
+	}
+	return db.CompareAndSwap(context.Background(), headKey(runID), 0, data)
+}
+
+// AdvanceChainHead runs the GuaranteedUpdate CAS loop described by the
+// Backend interface to atomically claim the next sequence number and
+// previous hash for a new event, so multiple vouchd instances can safely
+// append to the same run concurrently.
+func (db *DB) AdvanceChainHead(runID string, computeHash func(nextSeq int64, prevHash string) string) (nextSeq int64, prevHash, newHash string, err error) {
+	ctx := context.Background()
+	_, err = db.GuaranteedUpdate(ctx, headKey(runID), true, func(old []byte, _ ObjectMeta) ([]byte, time.Duration, error) {
+		head := chainHead{NextSeq: 0, LastHash: ""}
+		if len(old) > 0 {
+			if err := json.Unmarshal(old, &head); err != nil {
+				return nil, 0, fmt.Errorf("decode chain head: %w", err)
+			}
+		}
+		prevHash = head.LastHash
+		nextSeq = head.NextSeq
+		newHash = computeHash(nextSeq, prevHash)
+		head.NextSeq = nextSeq + 1
+		head.LastHash = newHash
+		data, err := json.Marshal(head)
+		return data, 0, err
+	})
+	if err != nil {
+		return 0, "", "", err
+	}
+	return nextSeq, prevHash, newHash, nil
+}
+
+// SaveEvent stores e at its sequence-keyed slot. The slot is expected to
+// not already exist: AdvanceChainHead claims the sequence number first.
+func (db *DB) SaveEvent(e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := db.CompareAndSwap(context.Background(), eventKey(e.RunID, seqKey(e.SeqIndex)), 0, data); err != nil {
+		return fmt.Errorf("save event %s: %w", e.ID, err)
+	}
+	return nil
+}
+
+func (db *DB) listEvents(runID string) ([]Event, error) {
+	raw, err := db.List(context.Background(), eventPrefix(runID))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	events := make([]Event, 0, len(keys))
+	for _, k := range keys {
+		var e Event
+		if err := json.Unmarshal(raw[k], &e); err != nil {
+			return nil, fmt.Errorf("decode event at %s: %w", k, err)
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// GetRecentEvents returns up to limit of the most recent events for runID,
+// oldest first.
+func (db *DB) GetRecentEvents(runID string, limit int) ([]Event, error) {
+	events, err := db.listEvents(runID)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events, nil
+}
+
+// GetUnanchoredEvents returns every event for runID that has no inclusion
+// proof yet, oldest first.
+func (db *DB) GetUnanchoredEvents(runID string) ([]Event, error) {
+	events, err := db.listEvents(runID)
+	if err != nil {
+		return nil, err
+	}
+	proofs, err := db.List(context.Background(), "proof:")
+	if err != nil {
+		return nil, err
+	}
+	covered := make(map[string]bool, len(proofs))
+	for _, raw := range proofs {
+		var p InclusionProof
+		if err := json.Unmarshal(raw, &p); err == nil {
+			covered[p.EventID] = true
+		}
+	}
+
+	var pending []Event
+	for _, e := range events {
+		if !covered[e.ID] {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+// GetTaskFailureCount returns how many times taskID has previously been
+// associated with a blocked event, across every run.
+func (db *DB) GetTaskFailureCount(taskID string) (int, error) {
+	raw, err := db.List(context.Background(), "event:")
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, data := range raw {
+		var e Event
+		if err := json.Unmarshal(data, &e); err != nil {
+			continue
+		}
+		if e.TaskID == taskID && e.WasBlocked {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SaveAnchor persists a new Bitcoin anchor record.
+func (db *DB) SaveAnchor(a Anchor) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return db.CompareAndSwap(context.Background(), anchorKey(a.RunID, a.ID), 0, data)
+}
+
+// SaveInclusionProof persists the Merkle inclusion proof for a single event
+// covered by an anchor.
+func (db *DB) SaveInclusionProof(p InclusionProof, encodedSiblings string) error {
+	p.Siblings = nil // encoded separately below; avoid double-storing
+	type stored struct {
+		InclusionProof
+		EncodedSiblings string `json:"encoded_siblings"`
+	}
+	data, err := json.Marshal(stored{InclusionProof: p, EncodedSiblings: encodedSiblings})
+	if err != nil {
+		return err
+	}
+	return db.CompareAndSwap(context.Background(), proofKey(p.EventID), 0, data)
+}
+
+// GetAnchors returns every anchor recorded for runID, oldest first.
+func (db *DB) GetAnchors(runID string) ([]Anchor, error) {
+	raw, err := db.List(context.Background(), anchorPrefix(runID))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	anchors := make([]Anchor, 0, len(keys))
+	for _, k := range keys {
+		var a Anchor
+		if err := json.Unmarshal(raw[k], &a); err != nil {
+			return nil, fmt.Errorf("decode anchor at %s: %w", k, err)
+		}
+		covered, err := db.eventsForAnchor(a.ID)
+		if err != nil {
+			return nil, err
+		}
+		a.CoveredEvents = covered
+		anchors = append(anchors, a)
+	}
+	return anchors, nil
+}
+
+func (db *DB) eventsForAnchor(anchorID string) ([]string, error) {
+	raw, err := db.List(context.Background(), "proof:")
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, data := range raw {
+		var p InclusionProof
+		if err := json.Unmarshal(data, &p); err != nil {
+			continue
+		}
+		if p.AnchorID == anchorID {
+			ids = append(ids, p.EventID)
+		}
+	}
+	return ids, nil
+}
+
+// GetInclusionProof returns the stored inclusion proof for eventID along
+// with its encoded sibling path.
+func (db *DB) GetInclusionProof(eventID string) (p InclusionProof, encodedSiblings string, err error) {
+	value, _, found, err := db.Get(context.Background(), proofKey(eventID))
+	if err != nil {
+		return InclusionProof{}, "", err
+	}
+	if !found {
+		return InclusionProof{}, "", fmt.Errorf("no inclusion proof for event %s", eventID)
+	}
+	var stored struct {
+		InclusionProof
+		EncodedSiblings string `json:"encoded_siblings"`
+	}
+	if err := json.Unmarshal(value, &stored); err != nil {
+		return InclusionProof{}, "", err
+	}
+	return stored.InclusionProof, stored.EncodedSiblings, nil
+}
@@ -0,0 +1,27 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+)
+
+// ApprovalDigest returns the content hash a "blocked" event's threshold
+// signature is computed over. Unlike Hash/PrevHash (which commit to the
+// event's position in the chain and are only known once AdvanceChainHead
+// has run), the approval digest depends only on the event's own content,
+// so a coordinator can run the FROST co-signing ceremony with external
+// approvers before the event ever claims a chain slot. It takes plain
+// fields rather than an Event so callers holding a proxy.Event (before it
+// has a RunID, SeqIndex, Hash, etc.) can compute the same digest that
+// audit.VerifyChain later recomputes from the persisted store.Event.
+func ApprovalDigest(id, eventType, method, taskID, policyID string, params map[string]interface{}) []byte {
+	h := sha256.New()
+	h.Write([]byte(id))
+	h.Write([]byte(eventType))
+	h.Write([]byte(method))
+	h.Write([]byte(taskID))
+	h.Write([]byte(policyID))
+	paramsJSON, _ := json.Marshal(params)
+	h.Write(paramsJSON)
+	return h.Sum(nil)
+}
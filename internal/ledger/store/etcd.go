@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdBackend lets many vouchd instances share one ledger: etcd's own
+// mod-revision and transactional compare-and-swap map directly onto
+// Backend, so this driver is mostly a thin adapter rather than a
+// reimplementation.
+type EtcdBackend struct {
+	client *clientv3.Client
+}
+
+// NewEtcdBackend dials the comma-separated endpoints in addr (the part of
+// an "etcd://host1:2379,host2:2379" DSN after the scheme).
+func NewEtcdBackend(addr string) (*EtcdBackend, error) {
+	endpoints := strings.Split(addr, ",")
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dial etcd %v: %w", endpoints, err)
+	}
+	return &EtcdBackend{client: client}, nil
+}
+
+func (b *EtcdBackend) Get(ctx context.Context, key string) ([]byte, ObjectMeta, bool, error) {
+	resp, err := b.client.Get(ctx, key)
+	if err != nil {
+		return nil, ObjectMeta{}, false, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, ObjectMeta{}, false, nil
+	}
+	kv := resp.Kvs[0]
+	return kv.Value, ObjectMeta{ModRevision: kv.ModRevision}, true, nil
+}
+
+func (b *EtcdBackend) List(ctx context.Context, keyPrefix string) (map[string][]byte, error) {
+	resp, err := b.client.Get(ctx, keyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]byte, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		out[string(kv.Key)] = kv.Value
+	}
+	return out, nil
+}
+
+func (b *EtcdBackend) CompareAndSwap(ctx context.Context, key string, expectedModRevision int64, newValue []byte) error {
+	txn := b.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", expectedModRevision)).
+		Then(clientv3.OpPut(key, string(newValue)))
+	resp, err := txn.Commit()
+	if err != nil {
+		return fmt.Errorf("etcd txn: %w", err)
+	}
+	if !resp.Succeeded {
+		return ErrConflict
+	}
+	return nil
+}
+
+func (b *EtcdBackend) GuaranteedUpdate(ctx context.Context, key string, mustCheckData bool, tryUpdate TryUpdateFunc) ([]byte, error) {
+	return RunGuaranteedUpdate(ctx,
+		func(ctx context.Context) ([]byte, ObjectMeta, bool, error) { return b.Get(ctx, key) },
+		func(ctx context.Context, expected int64, newValue []byte) error {
+			return b.CompareAndSwap(ctx, key, expected, newValue)
+		},
+		mustCheckData, tryUpdate)
+}
+
+func (b *EtcdBackend) Watch(ctx context.Context, key string) (<-chan WatchEvent, error) {
+	out := make(chan WatchEvent, 16)
+	watchCh := b.client.Watch(ctx, key)
+
+	go func() {
+		defer close(out)
+		for resp := range watchCh {
+			for _, ev := range resp.Events {
+				out <- WatchEvent{
+					Key:         string(ev.Kv.Key),
+					Value:       ev.Kv.Value,
+					ModRevision: ev.Kv.ModRevision,
+					Deleted:     ev.Type == clientv3.EventTypeDelete,
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (b *EtcdBackend) Close() error {
+	return b.client.Close()
+}
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteBackend is the default single-node Backend driver: a flat
+// key/value table with an auto-incrementing mod-revision column, which is
+// enough to satisfy Backend's CAS and GuaranteedUpdate contract even
+// though SQLite itself has no notion of revisions.
+type SQLiteBackend struct {
+	conn *sql.DB
+	mu   sync.Mutex // serializes CAS so the read-modify-write is atomic
+}
+
+// NewSQLiteBackend opens (and if necessary initializes) the SQLite
+// database at path.
+func NewSQLiteBackend(path string) (*SQLiteBackend, error) {
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	b := &SQLiteBackend{conn: conn}
+	if _, err := conn.Exec(`CREATE TABLE IF NOT EXISTS kv (
+		key TEXT PRIMARY KEY,
+		value BLOB,
+		mod_revision INTEGER
+	)`); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("migrate %s: %w", path, err)
+	}
+	return b, nil
+}
+
+func (b *SQLiteBackend) Get(ctx context.Context, key string) ([]byte, ObjectMeta, bool, error) {
+	var value []byte
+	var rev int64
+	row := b.conn.QueryRowContext(ctx, `SELECT value, mod_revision FROM kv WHERE key = ?`, key)
+	if err := row.Scan(&value, &rev); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ObjectMeta{}, false, nil
+		}
+		return nil, ObjectMeta{}, false, err
+	}
+	return value, ObjectMeta{ModRevision: rev}, true, nil
+}
+
+func (b *SQLiteBackend) List(ctx context.Context, keyPrefix string) (map[string][]byte, error) {
+	rows, err := b.conn.QueryContext(ctx, `SELECT key, value FROM kv WHERE key LIKE ? ESCAPE '\'`, escapeLike(keyPrefix)+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string][]byte)
+	for rows.Next() {
+		var k string
+		var v []byte
+		if err := rows.Scan(&k, &v); err != nil {
+			return nil, err
+		}
+		out[k] = v
+	}
+	return out, rows.Err()
+}
+
+func (b *SQLiteBackend) CompareAndSwap(ctx context.Context, key string, expectedModRevision int64, newValue []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var currentRev int64
+	row := b.conn.QueryRowContext(ctx, `SELECT mod_revision FROM kv WHERE key = ?`, key)
+	err := row.Scan(&currentRev)
+	switch {
+	case err == sql.ErrNoRows:
+		if expectedModRevision != 0 {
+			return ErrConflict
+		}
+		_, err = b.conn.ExecContext(ctx, `INSERT INTO kv (key, value, mod_revision) VALUES (?, ?, 1)`, key, newValue)
+		return err
+	case err != nil:
+		return err
+	default:
+		if currentRev != expectedModRevision {
+			return ErrConflict
+		}
+		res, err := b.conn.ExecContext(ctx, `UPDATE kv SET value = ?, mod_revision = mod_revision + 1 WHERE key = ? AND mod_revision = ?`, newValue, key, expectedModRevision)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrConflict
+		}
+		return nil
+	}
+}
+
+func (b *SQLiteBackend) GuaranteedUpdate(ctx context.Context, key string, mustCheckData bool, tryUpdate TryUpdateFunc) ([]byte, error) {
+	return RunGuaranteedUpdate(ctx,
+		func(ctx context.Context) ([]byte, ObjectMeta, bool, error) { return b.Get(ctx, key) },
+		func(ctx context.Context, expected int64, newValue []byte) error {
+			return b.CompareAndSwap(ctx, key, expected, newValue)
+		},
+		mustCheckData, tryUpdate)
+}
+
+// Watch is not implemented for SQLite: it has no native change feed, and a
+// single-node deployment has no other writer to watch for. Callers that
+// need cross-instance notification should use the etcd driver.
+func (b *SQLiteBackend) Watch(ctx context.Context, key string) (<-chan WatchEvent, error) {
+	return nil, fmt.Errorf("sqlite backend does not support Watch; use etcd:// for multi-instance deployments")
+}
+
+func (b *SQLiteBackend) Close() error {
+	return b.conn.Close()
+}
+
+func escapeLike(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `%`, `\%`)
+	s = strings.ReplaceAll(s, `_`, `\_`)
+	return s
+}
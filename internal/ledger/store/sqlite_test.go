@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+// TestCompareAndSwap_InsertConflict confirms that once a key exists, a
+// second CompareAndSwap with expectedModRevision 0 (i.e. "key must not
+// exist yet") is rejected with ErrConflict rather than silently
+// overwriting or reporting success for a write that didn't happen.
+func TestCompareAndSwap_InsertConflict(t *testing.T) {
+	backend, err := NewSQLiteBackend(":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite backend: %v", err)
+	}
+	defer backend.Close()
+
+	ctx := context.Background()
+	const key = "chain/head"
+
+	if err := backend.CompareAndSwap(ctx, key, 0, []byte("first")); err != nil {
+		t.Fatalf("first insert: %v", err)
+	}
+
+	if err := backend.CompareAndSwap(ctx, key, 0, []byte("second")); err != ErrConflict {
+		t.Fatalf("second insert: got %v, want ErrConflict", err)
+	}
+
+	value, meta, found, err := backend.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !found {
+		t.Fatal("key not found after insert")
+	}
+	if string(value) != "first" {
+		t.Fatalf("value = %q, want %q (the losing write must not have landed)", value, "first")
+	}
+	if meta.ModRevision != 1 {
+		t.Fatalf("mod_revision = %d, want 1", meta.ModRevision)
+	}
+}
+
+// TestCompareAndSwap_UpdateConflict confirms an UPDATE-path CAS against a
+// stale mod-revision is rejected with ErrConflict and leaves the stored
+// value untouched.
+func TestCompareAndSwap_UpdateConflict(t *testing.T) {
+	backend, err := NewSQLiteBackend(":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite backend: %v", err)
+	}
+	defer backend.Close()
+
+	ctx := context.Background()
+	const key = "chain/head"
+
+	if err := backend.CompareAndSwap(ctx, key, 0, []byte("v1")); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := backend.CompareAndSwap(ctx, key, 1, []byte("v2")); err != nil {
+		t.Fatalf("update to v2: %v", err)
+	}
+
+	// This call still targets mod_revision 1, which is now stale since
+	// the update above advanced it to 2.
+	if err := backend.CompareAndSwap(ctx, key, 1, []byte("v3-stale")); err != ErrConflict {
+		t.Fatalf("stale update: got %v, want ErrConflict", err)
+	}
+
+	value, meta, _, err := backend.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if string(value) != "v2" {
+		t.Fatalf("value = %q, want %q", value, "v2")
+	}
+	if meta.ModRevision != 2 {
+		t.Fatalf("mod_revision = %d, want 2", meta.ModRevision)
+	}
+}
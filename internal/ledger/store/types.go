@@ -0,0 +1,55 @@
+package store
+
+import "time"
+
+// Event is a single persisted ledger entry, hash-chained to the previous
+// event in the same run.
+type Event struct {
+	SeqIndex   int64                  `json:"seq_index"`
+	ID         string                 `json:"id"`
+	RunID      string                 `json:"run_id"`
+	Timestamp  time.Time              `json:"timestamp"`
+	EventType  string                 `json:"event_type"`
+	Method     string                 `json:"method"`
+	Params     map[string]interface{} `json:"params,omitempty"`
+	Response   map[string]interface{} `json:"response,omitempty"`
+	TaskID     string                 `json:"task_id,omitempty"`
+	TaskState  string                 `json:"task_state,omitempty"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	PolicyID   string                 `json:"policy_id,omitempty"`
+	RiskLevel  string                 `json:"risk_level,omitempty"`
+	WasBlocked bool                   `json:"was_blocked"`
+	Hash       string                 `json:"hash"`
+	PrevHash   string                 `json:"prev_hash"`
+	Signature  string                 `json:"signature"`
+}
+
+// SiblingHash is one step of a Merkle inclusion proof: the hash of the
+// sibling node and which side of the pair it occupies.
+type SiblingHash struct {
+	Hash string `json:"hash"`
+	Left bool   `json:"left"`
+}
+
+// InclusionProof lets a single event's leaf hash be folded up to the
+// Merkle root committed on-chain in the covering Anchor's TxID.
+type InclusionProof struct {
+	EventID     string        `json:"event_id"`
+	AnchorID    string        `json:"anchor_id"`
+	LeafHash    string        `json:"leaf_hash"`
+	Siblings    []SiblingHash `json:"siblings,omitempty"`
+	TxID        string        `json:"tx_id"`
+	BlockHeight int64         `json:"block_height"`
+}
+
+// Anchor records one Bitcoin OP_RETURN commitment covering a batch of
+// chain heads for a run.
+type Anchor struct {
+	ID            string    `json:"id"`
+	RunID         string    `json:"run_id"`
+	MerkleRoot    string    `json:"merkle_root"`
+	TxID          string    `json:"tx_id"`
+	BlockHeight   int64     `json:"block_height"`
+	CreatedAt     time.Time `json:"created_at"`
+	CoveredEvents []string  `json:"-"` // populated by GetAnchors from inclusion proofs
+}
@@ -0,0 +1,231 @@
+// Package ledger runs the asynchronous worker that admits intercepted MCP
+// events onto the hash chain, signs them, and periodically anchors the
+// chain to Bitcoin.
+package ledger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/vouch/internal/crypto"
+	"github.com/yourname/vouch/internal/ledger/store"
+	"github.com/yourname/vouch/internal/proxy"
+)
+
+// DefaultAnchorInterval is how often the worker flushes pending chain heads
+// into a new Bitcoin anchor when no --anchor-interval flag is given.
+const DefaultAnchorInterval = 10 * time.Minute
+
+// Worker admits intercepted events onto the hash chain and periodically
+// anchors it to Bitcoin. Because chain-head advancement goes through
+// Backend.GuaranteedUpdate, multiple Workers (e.g. several vouchd
+// instances) can safely share one Backend.
+type Worker struct {
+	db             *store.DB
+	signer         *crypto.ThresholdSigner
+	events         chan proxy.Event
+	anchorInterval time.Duration
+
+	mu       sync.Mutex
+	runID    string
+	healthy  bool
+	flushNow chan struct{}
+	done     chan struct{}
+}
+
+// NewWorker creates a Worker over backend, signing with the key at
+// keyPath. bufSize bounds the in-memory event channel. backend is
+// typically obtained via store.NewDB(dsn); NewWorker accepts the
+// interface directly so callers can share one Backend across several
+// Workers.
+func NewWorker(bufSize int, backend store.Backend, keyPath string) (*Worker, error) {
+	signer, err := crypto.LoadThresholdSigner(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("load signer: %w", err)
+	}
+	return &Worker{
+		db:             store.FromBackend(backend),
+		signer:         signer,
+		events:         make(chan proxy.Event, bufSize),
+		anchorInterval: DefaultAnchorInterval,
+		flushNow:       make(chan struct{}, 1),
+		done:           make(chan struct{}),
+	}, nil
+}
+
+// SetAnchorInterval overrides the default batching interval. It must be
+// called before Start.
+func (w *Worker) SetAnchorInterval(d time.Duration) {
+	w.anchorInterval = d
+}
+
+// Start creates the genesis block if needed and launches the event-chain
+// and anchoring goroutines.
+func (w *Worker) Start() error {
+	runID, err := w.db.GetRunID()
+	if err != nil {
+		return fmt.Errorf("get run id: %w", err)
+	}
+	if runID == "" {
+		runID = uuid.New().String()
+		genesis := genesisHash(runID)
+		if err := w.db.SaveRun(runID, "vouchd", genesis, w.signer.PublicKeyHex(), time.Now()); err != nil {
+			return fmt.Errorf("save run: %w", err)
+		}
+		if err := w.db.SeedChainHead(runID, genesis); err != nil {
+			return fmt.Errorf("seed chain head: %w", err)
+		}
+	}
+	w.runID = runID
+	w.healthy = true
+
+	go w.run()
+	go w.anchorLoop()
+	return nil
+}
+
+func genesisHash(runID string) string {
+	h := sha256.Sum256([]byte("vouch-genesis:" + runID))
+	return hex.EncodeToString(h[:])
+}
+
+// IsHealthy reports whether the worker can currently admit events.
+func (w *Worker) IsHealthy() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.healthy
+}
+
+// GetDB returns the underlying store, for callers (like stall intelligence)
+// that need read access without going through the event channel.
+func (w *Worker) GetDB() *store.DB {
+	return w.db
+}
+
+// GetSigner returns the worker's threshold signer, e.g. for FROST
+// co-signing a blocked event or rekeying.
+func (w *Worker) GetSigner() *crypto.ThresholdSigner {
+	return w.signer
+}
+
+// Submit enqueues event for asynchronous admission onto the chain.
+func (w *Worker) Submit(event proxy.Event) {
+	select {
+	case w.events <- event:
+	default:
+		log.Printf("[CRITICAL] event buffer full, dropping event %s", event.ID)
+		w.mu.Lock()
+		w.healthy = false
+		w.mu.Unlock()
+	}
+}
+
+func (w *Worker) run() {
+	for event := range w.events {
+		w.admit(event)
+	}
+}
+
+// admit signs event with the worker's own signer (SignSolo) once its
+// chain hash is known, and persists it. It is used for ordinary events
+// that don't require multi-party approval; see SubmitSigned for events
+// whose signature was produced by an external co-signing ceremony.
+//
+// SignSolo only works for a 1-of-1 group: a t>1 group has no automatic
+// co-signing path for ordinary (non-"blocked") events today, only the
+// human-driven ceremony handleStall runs for policy-matched ones. Rather
+// than discover that by calling claimAndSave and failing after the chain
+// head has already advanced past this event's slot — which would leave
+// that slot permanently unfilled, since SaveEvent never runs — admit
+// checks CanSignSolo first and marks the worker unhealthy without
+// claiming a slot at all, so a t>1 signer never corrupts the chain, it
+// just stops admitting ordinary events until reconfigured.
+func (w *Worker) admit(event proxy.Event) {
+	if !w.signer.CanSignSolo() {
+		log.Printf("[CRITICAL] cannot admit event %s: worker signer is not a 1-of-1 group, and ordinary events have no co-signing ceremony yet", event.ID)
+		w.mu.Lock()
+		w.healthy = false
+		w.mu.Unlock()
+		return
+	}
+	if err := w.claimAndSave(event, func(hash string) ([]byte, error) {
+		return w.signer.SignSolo([]byte(hash))
+	}); err != nil {
+		log.Printf("[CRITICAL] %v", err)
+	}
+}
+
+// SubmitSigned admits a pre-signed event onto the chain: used for
+// "blocked" events whose signature is a FROST aggregate that t external
+// approvers co-signed over store.ApprovalDigest(event) before the event
+// was allowed to proceed, rather than the worker's own SignSolo. Chain
+// position (Hash/PrevHash/SeqIndex) is still claimed here via
+// AdvanceChainHead exactly as for solo-signed events.
+func (w *Worker) SubmitSigned(event proxy.Event, signature []byte) error {
+	return w.claimAndSave(event, func(string) ([]byte, error) { return signature, nil })
+}
+
+// claimAndSave claims the next sequence number and previous hash from the
+// shared chain head via Backend.GuaranteedUpdate (safe even if another
+// vouchd instance is admitting concurrently), asks sign for a signature
+// over the resulting chain hash, and persists the event. It remains
+// unanchored (see store.GetUnanchoredEvents) until the next anchor batch
+// covers it.
+func (w *Worker) claimAndSave(event proxy.Event, sign func(hash string) ([]byte, error)) error {
+	if w.runID == "" {
+		return fmt.Errorf("worker not started")
+	}
+
+	seq, prevHash, hash, err := w.db.AdvanceChainHead(w.runID, func(nextSeq int64, prevHash string) string {
+		h := sha256.New()
+		h.Write([]byte(prevHash))
+		h.Write([]byte(event.ID))
+		h.Write([]byte(event.EventType))
+		h.Write([]byte(event.Method))
+		return hex.EncodeToString(h.Sum(nil))
+	})
+	if err != nil {
+		w.mu.Lock()
+		w.healthy = false
+		w.mu.Unlock()
+		return fmt.Errorf("advance chain head for %s: %w", event.ID, err)
+	}
+
+	sig, err := sign(hash)
+	if err != nil {
+		return fmt.Errorf("sign event %s: %w", event.ID, err)
+	}
+
+	rec := store.Event{
+		SeqIndex:   seq,
+		ID:         event.ID,
+		RunID:      w.runID,
+		Timestamp:  event.Timestamp,
+		EventType:  event.EventType,
+		Method:     event.Method,
+		Params:     event.Params,
+		Response:   event.Response,
+		TaskID:     event.TaskID,
+		TaskState:  event.TaskState,
+		ParentID:   event.ParentID,
+		PolicyID:   event.PolicyID,
+		RiskLevel:  event.RiskLevel,
+		WasBlocked: event.WasBlocked,
+		Hash:       hash,
+		PrevHash:   prevHash,
+		Signature:  hex.EncodeToString(sig),
+	}
+
+	if err := w.db.SaveEvent(rec); err != nil {
+		w.mu.Lock()
+		w.healthy = false
+		w.mu.Unlock()
+		return fmt.Errorf("persist event %s: %w", event.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package ledger
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yourname/vouch/internal/crypto"
+	"github.com/yourname/vouch/internal/ledger/store"
+	"github.com/yourname/vouch/internal/proxy"
+)
+
+// TestAdmit_ThresholdGroupCannotSoloSignDoesNotOrphanChainHead confirms
+// that when the worker's signer is a t>1 group (which SignSolo always
+// rejects), admit refuses the event before claiming a chain-head slot,
+// rather than advancing the head and then failing to sign, which would
+// leave that slot permanently unfilled.
+func TestAdmit_ThresholdGroupCannotSoloSignDoesNotOrphanChainHead(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{filepath.Join(dir, "a.key"), filepath.Join(dir, "b.key")}
+	if _, err := crypto.BootstrapThresholdGroup(2, 2, paths); err != nil {
+		t.Fatalf("bootstrap threshold group: %v", err)
+	}
+
+	backend, err := store.NewSQLiteBackend(":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite backend: %v", err)
+	}
+	defer backend.Close()
+
+	worker, err := NewWorker(16, backend, paths[0])
+	if err != nil {
+		t.Fatalf("new worker: %v", err)
+	}
+	if err := worker.Start(); err != nil {
+		t.Fatalf("start worker: %v", err)
+	}
+
+	worker.admit(proxy.Event{ID: "evt-1", Timestamp: time.Now(), EventType: "tool_call", Method: "file.read"})
+
+	if worker.IsHealthy() {
+		t.Fatal("worker should report unhealthy after failing to admit under a t>1 group")
+	}
+
+	events, err := worker.db.GetRecentEvents(worker.runID, 10)
+	if err != nil {
+		t.Fatalf("get recent events: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("got %d persisted events, want 0", len(events))
+	}
+
+	// Reconfigure the same backend/run with a 1-of-1 signer, as an
+	// operator recovering from the misconfiguration would, and confirm
+	// the next ordinary event lands at seq 0 rather than seq 1 — proving
+	// the earlier failed admit never claimed (and orphaned) a slot.
+	soloWorker, err := NewWorker(16, backend, filepath.Join(dir, "solo.key"))
+	if err != nil {
+		t.Fatalf("new solo worker: %v", err)
+	}
+	if err := soloWorker.Start(); err != nil {
+		t.Fatalf("start solo worker: %v", err)
+	}
+	soloWorker.admit(proxy.Event{ID: "evt-2", Timestamp: time.Now(), EventType: "tool_call", Method: "file.read"})
+
+	events, err = soloWorker.db.GetRecentEvents(soloWorker.runID, 10)
+	if err != nil {
+		t.Fatalf("get recent events: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d persisted events, want 1", len(events))
+	}
+	if events[0].SeqIndex != 0 {
+		t.Fatalf("event claimed seq %d, want 0 (seq 0 must not have been orphaned by the earlier failed admit)", events[0].SeqIndex)
+	}
+}
@@ -0,0 +1,145 @@
+package proxy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/cel-go/cel"
+)
+
+// celProgram aliases cel.Program so policy.go can declare PolicyRule's
+// compiled-expression field without importing cel-go itself; the zero
+// value (nil) means "no when expression".
+type celProgram = cel.Program
+
+// EvalContext is the per-call context a rule's `when` CEL expression (and
+// its shallow Conditions) are evaluated against. RiskLevel is filled in
+// by FindMatch from the candidate rule's own risk_level, since "risk" in
+// a `when` expression refers to the rule being tested, not some global
+// risk score.
+type EvalContext struct {
+	Method           string
+	Params           map[string]interface{}
+	TaskFailureCount int
+	RecentMethods    []string
+	RiskLevel        string
+}
+
+func (ctx EvalContext) activation() map[string]interface{} {
+	return map[string]interface{}{
+		"method": ctx.Method,
+		"params": ctx.Params,
+		"task": map[string]interface{}{
+			"failure_count":  ctx.TaskFailureCount,
+			"recent_methods": ctx.RecentMethods,
+		},
+		"time": map[string]interface{}{
+			"hour": time.Now().Hour(),
+		},
+		"risk": ctx.RiskLevel,
+	}
+}
+
+// celEnv is the single CEL environment every rule's `when` expression is
+// compiled against; it's built once since declaring variables is the
+// expensive part of cel.NewEnv.
+func celEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("method", cel.StringType),
+		cel.Variable("params", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("task", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("time", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("risk", cel.StringType),
+	)
+}
+
+// compileRules compiles every rule's When expression into a reusable
+// cel.Program, so evaluatePolicy never re-parses an expression per
+// request.
+func compileRules(cfg *PolicyConfig) error {
+	if !hasAnyWhen(cfg) {
+		return nil
+	}
+	env, err := celEnv()
+	if err != nil {
+		return fmt.Errorf("build policy expression environment: %w", err)
+	}
+	for i := range cfg.Policies {
+		rule := &cfg.Policies[i]
+		if rule.When == "" {
+			continue
+		}
+		ast, issues := env.Compile(rule.When)
+		if issues != nil && issues.Err() != nil {
+			return fmt.Errorf("policy %s: compile when expression %q: %w", rule.ID, rule.When, issues.Err())
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			return fmt.Errorf("policy %s: build when program: %w", rule.ID, err)
+		}
+		rule.program = prg
+	}
+	return nil
+}
+
+func hasAnyWhen(cfg *PolicyConfig) bool {
+	for _, rule := range cfg.Policies {
+		if rule.When != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWhen reports whether r's when expression (if any) holds for
+// ctx. A rule with no when expression always matches.
+func (r *PolicyRule) matchesWhen(ctx EvalContext) (bool, error) {
+	if r.program == nil {
+		return true, nil
+	}
+	out, _, err := r.program.Eval(ctx.activation())
+	if err != nil {
+		return false, fmt.Errorf("policy %s: evaluate when expression: %w", r.ID, err)
+	}
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("policy %s: when expression must evaluate to a bool, got %T", r.ID, out.Value())
+	}
+	return matched, nil
+}
+
+// FindMatch returns the first rule (in config order) whose method
+// pattern, shallow Conditions, and optional `when` expression all match
+// ctx, or nil if none do (an implicit allow). It considers every rule
+// regardless of Action; callers dispatch on the returned rule's Action
+// themselves (see VouchProxy.handleRequest).
+func (cfg *PolicyConfig) FindMatch(ctx EvalContext) (*PolicyRule, error) {
+	for i := range cfg.Policies {
+		rule := &cfg.Policies[i]
+
+		matchedMethod := false
+		for _, pattern := range rule.MatchMethods {
+			if MatchPattern(pattern, ctx.Method) {
+				matchedMethod = true
+				break
+			}
+		}
+		if !matchedMethod {
+			continue
+		}
+
+		if rule.Conditions != nil && !CheckConditions(rule.Conditions, ctx.Params) {
+			continue
+		}
+
+		ctx.RiskLevel = rule.RiskLevel
+		matched, err := rule.matchesWhen(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			return rule, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,72 @@
+package proxy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const whenPolicyYAML = `
+version: "1"
+policies:
+  - id: large-transfer
+    match_methods:
+      - "payment.transfer"
+    action: stall
+    risk_level: high
+    when: "params.amount > 1000 && risk == 'high'"
+  - id: catch-all
+    match_methods:
+      - "*"
+    action: allow
+`
+
+func loadWhenPolicy(t *testing.T) *PolicyConfig {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "vouch-policy.yaml")
+	if err := os.WriteFile(path, []byte(whenPolicyYAML), 0o644); err != nil {
+		t.Fatalf("write policy file: %v", err)
+	}
+	store, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	return store.Current()
+}
+
+// TestFindMatch_WhenExpression confirms a rule's `when` expression is
+// evaluated against the candidate rule's own risk_level and the call's
+// params, and that a request falling through it matches the next rule in
+// config order instead.
+func TestFindMatch_WhenExpression(t *testing.T) {
+	cfg := loadWhenPolicy(t)
+
+	rule, err := cfg.FindMatch(EvalContext{
+		Method: "payment.transfer",
+		Params: map[string]interface{}{"amount": 5000},
+	})
+	if err != nil {
+		t.Fatalf("find match: %v", err)
+	}
+	if rule == nil || rule.ID != "large-transfer" {
+		t.Fatalf("got rule %+v, want large-transfer", rule)
+	}
+}
+
+// TestFindMatch_WhenExpressionFalseFallsThrough confirms a call that
+// matches a rule's method pattern but not its `when` expression is not
+// matched by that rule, and instead falls through to a later rule.
+func TestFindMatch_WhenExpressionFalseFallsThrough(t *testing.T) {
+	cfg := loadWhenPolicy(t)
+
+	rule, err := cfg.FindMatch(EvalContext{
+		Method: "payment.transfer",
+		Params: map[string]interface{}{"amount": 10},
+	})
+	if err != nil {
+		t.Fatalf("find match: %v", err)
+	}
+	if rule == nil || rule.ID != "catch-all" {
+		t.Fatalf("got rule %+v, want catch-all", rule)
+	}
+}
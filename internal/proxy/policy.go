@@ -0,0 +1,222 @@
+// Package proxy holds the wire-level event model and policy matching used
+// by the VouchProxy reverse proxy to decide which intercepted MCP calls to
+// stall, redact, rate-limit, shadow, or allow through.
+package proxy
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// Event is the wire-level record of one intercepted MCP call or response,
+// before it is persisted (and hash-chained) by the ledger worker.
+type Event struct {
+	ID         string
+	Timestamp  time.Time
+	EventType  string
+	Method     string
+	Params     map[string]interface{}
+	Response   map[string]interface{}
+	TaskID     string
+	TaskState  string
+	ParentID   string
+	PolicyID   string
+	RiskLevel  string
+	WasBlocked bool
+}
+
+// Condition is a single shallow parameter check, e.g. `amount > 1000`.
+type Condition struct {
+	Param    string      `yaml:"param"`
+	Operator string      `yaml:"operator"`
+	Value    interface{} `yaml:"value"`
+}
+
+// PolicyRule is one entry in vouch-policy.yaml. Action is one of "stall",
+// "redact-only", "shadow", "rate-limit(N/min)" (see ParseRateLimit), or
+// any other value, which is treated as an implicit allow (Redact still
+// applies if set). When, if present, is a CEL expression compiled once at
+// load time (see EvalContext) and must hold, alongside MatchMethods and
+// Conditions, for the rule to match.
+type PolicyRule struct {
+	ID           string      `yaml:"id"`
+	MatchMethods []string    `yaml:"match_methods"`
+	Action       string      `yaml:"action"`
+	RiskLevel    string      `yaml:"risk_level"`
+	Conditions   []Condition `yaml:"conditions"`
+	Redact       []string    `yaml:"redact"`
+	When         string      `yaml:"when"`
+
+	program celProgram // compiled from When by compileRules; nil if When == ""
+}
+
+// PolicyConfig is the parsed contents of vouch-policy.yaml.
+type PolicyConfig struct {
+	Version  string       `yaml:"version"`
+	Policies []PolicyRule `yaml:"policies"`
+}
+
+// PolicyStore holds a policy ruleset that can be hot-reloaded without
+// restarting VouchProxy. Callers read the current ruleset via Current;
+// LoadPolicy's fsnotify watcher swaps it atomically whenever the backing
+// file changes on disk, so every in-flight request still sees a
+// consistent snapshot.
+type PolicyStore struct {
+	current atomic.Pointer[PolicyConfig]
+}
+
+// Current returns the ruleset currently in effect.
+func (s *PolicyStore) Current() *PolicyConfig {
+	return s.current.Load()
+}
+
+// LoadPolicy reads and parses the policy file at path, compiles every
+// rule's `when` expression, and starts a background watcher that reloads
+// and atomically swaps the ruleset whenever the file is rewritten. A
+// reload that fails to parse or compile is logged and discarded; the
+// previous ruleset keeps serving rather than taking VouchProxy down.
+func LoadPolicy(path string) (*PolicyStore, error) {
+	cfg, err := loadAndCompile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("start policy watcher: %w", err)
+	}
+	if err := watcher.Add(path); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("watch policy file %s: %w", path, err)
+	}
+
+	store := &PolicyStore{}
+	store.current.Store(cfg)
+	go store.watch(path, watcher)
+	return store, nil
+}
+
+func (s *PolicyStore) watch(path string, watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+	for event := range watcher.Events {
+		if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+			continue
+		}
+		cfg, err := loadAndCompile(path)
+		if err != nil {
+			log.Printf("[policy] reload of %s failed, keeping previous ruleset: %v", path, err)
+			continue
+		}
+		s.current.Store(cfg)
+		log.Printf("[policy] reloaded %s: version %s, %d rules", path, cfg.Version, len(cfg.Policies))
+	}
+}
+
+func loadAndCompile(path string) (*PolicyConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg PolicyConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	if err := compileRules(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+var rateLimitPattern = regexp.MustCompile(`^rate-limit\((\d+)/min\)$`)
+
+// ParseRateLimit reports the requests-per-minute budget encoded in an
+// action string like "rate-limit(20/min)", and whether action is in that
+// form at all.
+func ParseRateLimit(action string) (perMinute int, ok bool) {
+	m := rateLimitPattern.FindStringSubmatch(action)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// MatchPattern reports whether method matches pattern, where pattern may
+// use a trailing "*" as a wildcard (e.g. "file.*" matches "file.write").
+func MatchPattern(pattern, method string) bool {
+	if pattern == method {
+		return true
+	}
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(method, strings.TrimSuffix(pattern, "*"))
+	}
+	return false
+}
+
+// CheckConditions evaluates a rule's shallow parameter conditions against
+// the call's params. All conditions must hold.
+func CheckConditions(conditions []Condition, params map[string]interface{}) bool {
+	for _, c := range conditions {
+		val, ok := params[c.Param]
+		if !ok {
+			return false
+		}
+		if !evalCondition(val, c.Operator, c.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func evalCondition(got interface{}, op string, want interface{}) bool {
+	switch op {
+	case "eq", "":
+		return got == want
+	case "neq":
+		return got != want
+	case "gt", "gte", "lt", "lte":
+		gf, gok := toFloat(got)
+		wf, wok := toFloat(want)
+		if !gok || !wok {
+			return false
+		}
+		switch op {
+		case "gt":
+			return gf > wf
+		case "gte":
+			return gf >= wf
+		case "lt":
+			return gf < wf
+		case "lte":
+			return gf <= wf
+		}
+	case "contains":
+		s, ok := got.(string)
+		sub, ok2 := want.(string)
+		return ok && ok2 && strings.Contains(s, sub)
+	}
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
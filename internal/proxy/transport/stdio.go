@@ -0,0 +1,151 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// maxMessageBytes bounds one framed JSON-RPC message read by these
+// transports. It is larger than the request body cap that
+// VouchProxy.extractTaskMetadata enforces, so a request between the two
+// limits is read here and then rejected by the handler.
+const maxMessageBytes = 16 * 1024 * 1024
+
+// StdioTransport spawns the downstream MCP server as a child process and
+// relays newline-delimited JSON-RPC messages between it and vouchd's own
+// stdin/stdout, running Handler over every message in both directions.
+type StdioTransport struct {
+	Command string
+	Args    []string
+	Handler MessageHandler
+
+	In  io.Reader // client requests; defaults to os.Stdin
+	Out io.Writer // responses back to the client; defaults to os.Stdout
+}
+
+// Run starts the downstream process and blocks relaying messages until
+// either direction's pipe closes or the child exits.
+func (t *StdioTransport) Run() error {
+	cmd := exec.Command(t.Command, t.Args...)
+	cmd.Stderr = os.Stderr
+
+	downstreamIn, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("open downstream stdin: %w", err)
+	}
+	downstreamOut, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("open downstream stdout: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start downstream MCP server %s: %w", t.Command, err)
+	}
+
+	in, out := t.In, t.Out
+	if in == nil {
+		in = os.Stdin
+	}
+	if out == nil {
+		out = os.Stdout
+	}
+
+	relayErr := make(chan error, 2)
+	go func() { relayErr <- relayRequests(in, downstreamIn, out, t.Handler) }()
+	go func() { relayErr <- relayResponses(downstreamOut, out, t.Handler) }()
+
+	if err := <-relayErr; err != nil {
+		_ = cmd.Process.Kill()
+		return err
+	}
+	return cmd.Wait()
+}
+
+// relayRequests reads newline-framed client requests from src, runs each
+// through handler, and writes the ones that pass on to downstream;
+// rejected requests' JSON-RPC error is written straight back to
+// rejectOut instead.
+//
+// handler.HandleRequest runs in its own goroutine per message rather than
+// inline in the scan loop: a stalled request (e.g. waiting on a human
+// approval via /api/approve/) can block for an arbitrary amount of time,
+// and with only one reader goroutine for the whole connection, handling
+// requests synchronously would head-of-line-block every other pipelined
+// request behind it. downstream and rejectOut are shared across those
+// goroutines, so writes to each are serialized with their own mutex;
+// nothing here guarantees requests reach downstream in the order they
+// were read, which is fine since MCP clients correlate responses by
+// JSON-RPC id rather than arrival order.
+func relayRequests(src io.Reader, downstream io.Writer, rejectOut io.Writer, handler MessageHandler) error {
+	var downstreamMu, rejectMu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	var errOnce sync.Once
+	recordErr := func(err error) {
+		errOnce.Do(func() { firstErr = err })
+	}
+
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageBytes)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		msg := append([]byte(nil), line...)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if reject := handler.HandleRequest(msg); reject != nil {
+				rejectMu.Lock()
+				_, err := rejectOut.Write(append(reject, '\n'))
+				rejectMu.Unlock()
+				if err != nil {
+					recordErr(err)
+				}
+				return
+			}
+
+			downstreamMu.Lock()
+			_, err := downstream.Write(append(msg, '\n'))
+			downstreamMu.Unlock()
+			if err != nil {
+				recordErr(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return firstErr
+}
+
+// relayResponses reads newline-framed responses and notifications from
+// the downstream server, runs each through handler, and forwards it
+// unmodified to the client.
+func relayResponses(src io.Reader, dst io.Writer, handler MessageHandler) error {
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageBytes)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		msg := append([]byte(nil), line...)
+
+		handler.HandleResponse(msg)
+		if _, err := dst.Write(append(msg, '\n')); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
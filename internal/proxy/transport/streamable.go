@@ -0,0 +1,166 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// StreamableHTTPTransport implements the MCP "streamable HTTP" transport:
+// a client POSTs one JSON-RPC request, and the downstream server replies
+// either with a single JSON body or a chunked `text/event-stream` of
+// `event: message` frames — server-initiated notifications (no `id`)
+// interleaved with the eventual response. It runs Handler over every
+// message either way.
+type StreamableHTTPTransport struct {
+	UpstreamURL string
+	Handler     MessageHandler
+	Client      *http.Client // defaults to http.DefaultClient
+}
+
+func (t *StreamableHTTPTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if reject := t.Handler.HandleRequest(body); reject != nil {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(reject)
+		return
+	}
+
+	client := t.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	upstreamReq, err := http.NewRequest(http.MethodPost, t.UpstreamURL, bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	upstreamReq.Header.Set("Content-Type", "application/json")
+	upstreamReq.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := client.Do(upstreamReq)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.relayJSON(w, resp)
+		return
+	}
+	t.relaySSE(w, resp)
+}
+
+// relayJSON handles the non-streaming case: a single JSON-RPC response
+// body, no SSE framing involved.
+func (t *StreamableHTTPTransport) relayJSON(w http.ResponseWriter, resp *http.Response) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	t.Handler.HandleResponse(body)
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(body)
+}
+
+// relaySSE streams the downstream response frame by frame: every
+// `message` frame (a JSON-RPC response or a server notification with no
+// `id`, both handled identically) runs through Handler as it arrives, and
+// is forwarded to the client immediately rather than buffered until the
+// stream ends.
+func (t *StreamableHTTPTransport) relaySSE(w http.ResponseWriter, resp *http.Response) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	_ = scanSSEFrames(bufio.NewReader(resp.Body), func(frame sseFrame) error {
+		if frame.Event == "" || frame.Event == "message" {
+			t.Handler.HandleResponse(frame.Data)
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\n", frame.Event); err != nil {
+			return err
+		}
+		for _, line := range bytes.Split(frame.Data, []byte("\n")) {
+			if _, err := fmt.Fprintf(w, "data: %s\n", line); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+}
+
+// sseFrame is one `event: ...\ndata: ...\n\n` Server-Sent Events frame.
+// Per the SSE spec, multiple `data:` lines in one frame are joined with
+// newlines.
+type sseFrame struct {
+	Event string
+	Data  []byte
+}
+
+// scanSSEFrames reads SSE frames from r, calling onFrame once per
+// complete (blank-line-terminated) frame. Reading line by line via
+// bufio.Reader means a frame split across TCP segments is reassembled
+// transparently: ReadString blocks for more data rather than returning a
+// partial line. onFrame is not called for out-of-spec stray bytes (bare
+// comments, `id:`/`retry:` lines) since VouchProxy only needs `message`
+// frames.
+func scanSSEFrames(r *bufio.Reader, onFrame func(sseFrame) error) error {
+	var cur sseFrame
+	var data bytes.Buffer
+	haveFrame := false
+
+	for {
+		line, err := r.ReadString('\n')
+		if len(line) > 0 {
+			trimmed := strings.TrimRight(line, "\r\n")
+			switch {
+			case trimmed == "":
+				if haveFrame {
+					cur.Data = bytes.TrimSuffix(data.Bytes(), []byte("\n"))
+					if ferr := onFrame(cur); ferr != nil {
+						return ferr
+					}
+				}
+				cur, data, haveFrame = sseFrame{}, bytes.Buffer{}, false
+			case strings.HasPrefix(trimmed, "event:"):
+				cur.Event = strings.TrimSpace(strings.TrimPrefix(trimmed, "event:"))
+				haveFrame = true
+			case strings.HasPrefix(trimmed, "data:"):
+				data.WriteString(strings.TrimPrefix(strings.TrimPrefix(trimmed, "data:"), " "))
+				data.WriteByte('\n')
+				haveFrame = true
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Package transport runs an MCP session over one of several wire
+// formats — an HTTP reverse proxy, framed stdio, or streamable HTTP
+// (chunked SSE) — while keeping policy, stall, and ledger logic out of
+// the wire-level details. Each transport feeds every JSON-RPC message it
+// sees through a MessageHandler before relaying it.
+package transport
+
+// MessageHandler applies policy, stall, and ledger logic to one JSON-RPC
+// message at a time. VouchProxy implements this once and every transport
+// in this package drives it the same way, so adding a transport never
+// touches policy or ledger code.
+type MessageHandler interface {
+	// HandleRequest inspects a JSON-RPC request from the client before
+	// it reaches the downstream MCP server. A non-nil return is a
+	// JSON-RPC error response the transport must send straight back to
+	// the caller instead of forwarding body.
+	HandleRequest(body []byte) (reject []byte)
+
+	// HandleResponse inspects a JSON-RPC response or notification from
+	// the downstream MCP server. Transports forward body unmodified
+	// regardless of what HandleResponse does; it exists so the handler
+	// can record ledger events and update task state.
+	HandleResponse(body []byte)
+}
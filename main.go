@@ -2,7 +2,11 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -15,8 +19,11 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/yourname/vouch/internal/assert"
+	"github.com/yourname/vouch/internal/crypto"
 	"github.com/yourname/vouch/internal/ledger"
+	"github.com/yourname/vouch/internal/ledger/store"
 	"github.com/yourname/vouch/internal/proxy"
+	"github.com/yourname/vouch/internal/proxy/transport"
 )
 
 // MCPRequest represents a Model Context Protocol JSON-RPC request
@@ -35,66 +42,106 @@ type MCPResponse struct {
 	Error   map[string]interface{} `json:"error,omitempty"`
 }
 
-// VouchProxy is the main proxy server
+// VouchProxy is the main proxy server. It also implements http.RoundTripper
+// (see RoundTrip) so the HTTP transport can genuinely short-circuit a
+// blocked or rejected request instead of merely logging it, and
+// transport.MessageHandler so the stdio and streamable-HTTP transports
+// share the identical policy/stall/ledger pipeline.
 type VouchProxy struct {
-	proxy           *httputil.ReverseProxy
-	worker          *ledger.Worker
-	activeTasks     *sync.Map // task_id -> state
-	policy          *proxy.PolicyConfig
-	stallSignals    *sync.Map // Maps event ID to approval channel
-	lastEventByTask *sync.Map // task_id -> last_event_id
+	proxy               *httputil.ReverseProxy
+	upstreamTransport   http.RoundTripper // wrapped transport for approved requests; nil means http.DefaultTransport
+	worker              *ledger.Worker
+	activeTasks         *sync.Map // task_id -> state
+	policy              *proxy.PolicyStore
+	stallSignals        *sync.Map // event ID -> *stallSession, the in-flight FROST co-signing ceremony for that blocked event
+	lastEventByTask     *sync.Map // task_id -> last_event_id
+	recentMethodsByTask *sync.Map // task_id -> []string, bounded window for `when` expressions' task.recent_methods
+	rateWindows         *sync.Map // policy rule ID -> *rateWindow, for rate-limit(N/min) actions
+	rekeyToken          string    // shared secret required in the X-Vouch-Rekey-Token header for POST /api/rekey
+}
+
+// rateWindow is a fixed one-minute window counter backing one policy rule's
+// rate-limit(N/min) action.
+type rateWindow struct {
+	mu    sync.Mutex
+	start time.Time
+	count int
+}
+
+// stallSession coordinates one "blocked" event's FROST co-signing: the
+// daemon (participant 1) and up to threshold-1 external approvers each
+// publish a Round1 nonce commitment, then — once the signing set is fixed
+// at exactly threshold commitments — each submits its Round2 partial
+// signature computed against that set. Once threshold partials arrive,
+// they are aggregated into the event's final signature and the stalled
+// request is allowed to proceed.
+type stallSession struct {
+	event     proxy.Event
+	threshold int
+
+	mu          sync.Mutex
+	commitments map[int]*crypto.NonceCommitment
+	partials    map[int]*crypto.PartialSignature
+	fixed       bool // true once the signing set has been frozen at `threshold` commitments
+
+	done chan error // nil: admitted to the chain; non-nil: rejected or failed
 }
 
 func main() {
 	log.Println("Vouch (Agent Analytics & Safety) - The Interceptor")
 
-	// Load policy
+	anchorInterval := flag.Duration("anchor-interval", ledger.DefaultAnchorInterval,
+		"how often to batch pending chain heads into a Bitcoin anchor")
+	storeDSN := flag.String("store", "sqlite://vouch.db",
+		"ledger storage backend: sqlite://path, etcd://host:port,..., or postgres://...")
+	transportName := flag.String("transport", "http",
+		"MCP transport to speak: http (reverse proxy), stdio, or streamable")
+	downstreamURL := flag.String("downstream", "http://localhost:8080",
+		"downstream MCP server URL (http and streamable transports)")
+	downstreamCmd := flag.String("downstream-cmd", "",
+		"downstream MCP server command to spawn, e.g. \"mcp-server --flag\" (stdio transport)")
+	rekeyToken := flag.String("rekey-token", "",
+		"shared secret required in the X-Vouch-Rekey-Token header to authorize POST /api/rekey; rekey is refused entirely if empty")
+	flag.Parse()
+
+	// Load policy; LoadPolicy hot-reloads vouch-policy.yaml in the
+	// background, so policy is a PolicyStore rather than a fixed snapshot.
 	policy, err := proxy.LoadPolicy("vouch-policy.yaml")
 	if err != nil {
 		log.Fatalf("Failed to load policy: %v", err)
 	}
-	log.Printf("Loaded policy version %s with %d rules", policy.Version, len(policy.Policies))
+	log.Printf("Loaded policy version %s with %d rules", policy.Current().Version, len(policy.Current().Policies))
 
-	// Create target URL
-	targetURL, err := url.Parse("http://localhost:8080")
+	// Open the ledger storage backend and initialize the worker on top of it
+	backend, err := store.NewDB(*storeDSN)
 	if err != nil {
-		log.Fatalf("Failed to parse target URL: %v", err)
+		log.Fatalf("Failed to open store %s: %v", *storeDSN, err)
 	}
-
-	// Create proxy
-	proxy := httputil.NewSingleHostReverseProxy(targetURL)
-
-	// Initialize ledger worker with database and crypto
-	worker, err := ledger.NewWorker(1000, "vouch.db", ".vouch_key")
+	worker, err := ledger.NewWorker(1000, backend, ".vouch_key")
 	if err != nil {
 		log.Fatalf("Failed to initialize worker: %v", err)
 	}
+	worker.SetAnchorInterval(*anchorInterval)
 
 	// Start worker (creates genesis block if needed)
 	if err := worker.Start(); err != nil {
 		log.Fatalf("Failed to start worker: %v", err)
 	}
 
-	// Create Vouch proxy
+	// Create Vouch proxy. It implements transport.MessageHandler, so the
+	// same policy/stall/ledger logic below runs identically regardless of
+	// which wire transport is selected.
 	vouchProxy := &VouchProxy{
-		proxy:           proxy,
-		worker:          worker,
-		activeTasks:     &sync.Map{}, // task_id -> state
-		policy:          policy,
-		stallSignals:    &sync.Map{}, // event_id -> chan struct{}
-		lastEventByTask: &sync.Map{}, // task_id -> last_event_id
+		worker:              worker,
+		activeTasks:         &sync.Map{}, // task_id -> state
+		policy:              policy,
+		stallSignals:        &sync.Map{}, // event_id -> *stallSession
+		lastEventByTask:     &sync.Map{}, // task_id -> last_event_id
+		recentMethodsByTask: &sync.Map{}, // task_id -> []string
+		rateWindows:         &sync.Map{}, // policy rule ID -> *rateWindow
+		rekeyToken:          *rekeyToken,
 	}
 
-	// Custom director to intercept requests
-	originalDirector := proxy.Director
-	proxy.Director = func(req *http.Request) {
-		originalDirector(req)
-		vouchProxy.interceptRequest(req)
-	}
-
-	// Custom response modifier
-	proxy.ModifyResponse = vouchProxy.interceptResponse
-
 	// Start API server for CLI commands (approval/rejection)
 	go func() {
 		apiMux := http.NewServeMux()
@@ -109,61 +156,245 @@ func main() {
 		}
 	}()
 
-	// Start proxy server
-	listenAddr := ":9999"
-	log.Printf("Proxying :9999 -> :8080")
 	log.Printf("Event buffer size: 1000")
+	log.Printf("Anchor interval: %s", *anchorInterval)
 	log.Printf("Policy engine: ACTIVE")
+
+	switch *transportName {
+	case "stdio":
+		runStdioTransport(vouchProxy, *downstreamCmd)
+	case "streamable":
+		runStreamableTransport(vouchProxy, *downstreamURL)
+	case "http":
+		runHTTPTransport(vouchProxy, *downstreamURL)
+	default:
+		log.Fatalf("unknown --transport %q (want http, stdio, or streamable)", *transportName)
+	}
+}
+
+// runHTTPTransport serves the original reverse-proxy transport.
+// VouchProxy itself is installed as the proxy's Transport (see RoundTrip)
+// so a blocked or rejected request is genuinely short-circuited — it
+// never dials downstreamURL — rather than merely logged after the fact.
+func runHTTPTransport(v *VouchProxy, downstreamURL string) {
+	targetURL, err := url.Parse(downstreamURL)
+	if err != nil {
+		log.Fatalf("Failed to parse downstream URL: %v", err)
+	}
+
+	reverseProxy := httputil.NewSingleHostReverseProxy(targetURL)
+	reverseProxy.Transport = v
+	reverseProxy.ModifyResponse = v.interceptResponse
+	v.proxy = reverseProxy
+
+	listenAddr := ":9999"
+	log.Printf("Proxying %s -> %s", listenAddr, downstreamURL)
 	log.Printf("Ready to intercept MCP traffic on %s\n", listenAddr)
+	if err := http.ListenAndServe(listenAddr, reverseProxy); err != nil {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
 
-	if err := http.ListenAndServe(listenAddr, proxy); err != nil {
+// runStdioTransport spawns downstreamCmd as a child process and relays
+// framed JSON-RPC over vouchd's own stdin/stdout.
+func runStdioTransport(v *VouchProxy, downstreamCmd string) {
+	if downstreamCmd == "" {
+		log.Fatalf("--downstream-cmd is required for --transport=stdio")
+	}
+	parts := strings.Fields(downstreamCmd)
+
+	t := &transport.StdioTransport{Command: parts[0], Args: parts[1:], Handler: v}
+	log.Printf("Running stdio transport, downstream: %s", downstreamCmd)
+	if err := t.Run(); err != nil {
+		log.Fatalf("stdio transport failed: %v", err)
+	}
+}
+
+// runStreamableTransport serves the MCP streamable-HTTP transport,
+// forwarding each request to downstreamURL and relaying its response
+// (plain JSON or chunked SSE) back to the caller frame by frame.
+func runStreamableTransport(v *VouchProxy, downstreamURL string) {
+	t := &transport.StreamableHTTPTransport{UpstreamURL: downstreamURL, Handler: v}
+
+	listenAddr := ":9999"
+	log.Printf("Streamable-HTTP transport listening on %s -> %s", listenAddr, downstreamURL)
+	if err := http.ListenAndServe(listenAddr, t); err != nil {
 		log.Fatalf("Server failed: %v", err)
 	}
 }
 
-// interceptRequest intercepts and analyzes incoming requests
-func (v *VouchProxy) interceptRequest(req *http.Request) {
+// RoundTrip implements http.RoundTripper. Installed as the reverse
+// proxy's Transport, it runs the full metadata → health → policy →
+// stall pipeline before ever dialing downstream: a blocked or rejected
+// request returns a synthesized JSON-RPC error response without
+// touching the network, and an approved request is forwarded
+// byte-for-byte via the wrapped upstream transport. Because this runs
+// as a RoundTripper rather than a Director hook, handleStall's wait on
+// the co-signing ceremony is canceled the moment the caller disconnects
+// (req.Context()), instead of leaking until the ceremony resolves.
+func (v *VouchProxy) RoundTrip(req *http.Request) (*http.Response, error) {
 	if req.Method != http.MethodPost {
-		return
+		return v.upstream().RoundTrip(req)
 	}
 
 	bodyBytes, err := io.ReadAll(req.Body)
 	if err != nil {
-		log.Printf("Failed to read request body: %v", err)
-		return
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	if httpStatus, code, message := v.handleRequest(req.Context(), bodyBytes); httpStatus != 0 {
+		log.Printf("[BLOCKED] %s %s (%d): %s", req.Method, req.URL.Path, httpStatus, message)
+		return jsonRPCResponse(req, httpStatus, code, message), nil
 	}
-	req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 
+	req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+	req.ContentLength = int64(len(bodyBytes))
+	return v.upstream().RoundTrip(req)
+}
+
+// upstream is the transport approved requests are forwarded through.
+func (v *VouchProxy) upstream() http.RoundTripper {
+	if v.upstreamTransport != nil {
+		return v.upstreamTransport
+	}
+	return http.DefaultTransport
+}
+
+// jsonRPCResponse synthesizes the *http.Response RoundTrip returns in
+// place of forwarding req upstream.
+func jsonRPCResponse(req *http.Request, httpStatus, code int, message string) *http.Response {
+	body := jsonRPCError(code, message)
+	return &http.Response{
+		Status:        http.StatusText(httpStatus),
+		StatusCode:    httpStatus,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// HandleRequest implements transport.MessageHandler for the stdio and
+// streamable-HTTP transports: it runs the same metadata → health →
+// policy → enforcement pipeline as RoundTrip, returning a JSON-RPC
+// error to send straight back to the caller on failure.
+func (v *VouchProxy) HandleRequest(body []byte) []byte {
+	if httpStatus, code, message := v.handleRequest(context.Background(), body); httpStatus != 0 {
+		return jsonRPCError(code, message)
+	}
+	return nil
+}
+
+// handleRequest runs the transport-agnostic request pipeline: parse and
+// validate, check ledger health, evaluate policy, enforce the matched
+// rule (if any), then record and submit the tool_call event. A non-zero
+// httpStatus means the request was rejected; callers map it to whatever
+// their transport needs (an HTTP status code, or a JSON-RPC error body).
+// ctx is passed through to handleStall so the FROST co-signing wait is
+// canceled alongside the caller, where the caller's own context supports
+// cancellation (the HTTP transport); other transports pass
+// context.Background().
+func (v *VouchProxy) handleRequest(ctx context.Context, body []byte) (httpStatus int, code int, message string) {
 	// 1. Extract Metadata
-	mcpReq, taskID, taskState, err := v.extractTaskMetadata(bodyBytes)
+	mcpReq, taskID, taskState, err := v.extractTaskMetadata(body)
 	if err != nil {
-		v.sendErrorResponse(req, http.StatusBadRequest, -32000, err.Error())
-		return
+		return http.StatusBadRequest, -32000, err.Error()
 	}
 
 	// 2. Health Check
 	if !v.worker.IsHealthy() {
-		v.sendErrorResponse(req, http.StatusServiceUnavailable, -32000, "Ledger Storage Failure")
-		return
+		return http.StatusServiceUnavailable, -32000, "Ledger Storage Failure"
 	}
 
 	// 3. Policy Evaluation
-	shouldStall, matchedRule, err := v.evaluatePolicy(mcpReq.Method, mcpReq.Params)
+	matchedRule, err := v.evaluatePolicy(taskID, mcpReq.Method, mcpReq.Params)
 	if err != nil {
-		v.sendErrorResponse(req, http.StatusBadRequest, -32000, "Policy violation")
-		return
+		return http.StatusBadRequest, -32000, "Policy violation"
 	}
 
-	// 4. Handle Stall (Human-in-the-loop)
-	if shouldStall {
-		if err := v.handleStall(taskID, taskState, mcpReq, matchedRule); err != nil {
-			v.sendErrorResponse(req, http.StatusForbidden, -32000, "Stall rejected or failed")
-			return
+	// 4. Act on the matched rule, if any
+	if matchedRule != nil {
+		if err := v.enforceRule(ctx, taskID, taskState, mcpReq, matchedRule); err != nil {
+			return http.StatusForbidden, -32000, "Stall rejected or failed"
 		}
 	}
 
 	// 5. Finalize Event & Submit
+	v.recordRecentMethod(taskID, mcpReq.Method)
 	v.submitToolCallEvent(taskID, taskState, mcpReq, matchedRule)
+	return 0, 0, ""
+}
+
+// enforceRule carries out whatever rule's Action calls for. "stall"
+// always runs the FROST co-signing ceremony; "rate-limit(N/min)" falls
+// back to the same ceremony once its budget is exceeded; "shadow" only
+// logs what would have happened; any other action (including
+// "redact-only" and plain allow) is a no-op here, since submitToolCallEvent
+// applies rule.Redact regardless of action.
+func (v *VouchProxy) enforceRule(ctx context.Context, taskID, taskState string, mcpReq *MCPRequest, rule *proxy.PolicyRule) error {
+	switch {
+	case rule.Action == "stall":
+		return v.handleStall(ctx, taskID, taskState, mcpReq, rule)
+
+	case rule.Action == "shadow":
+		log.Printf("[SHADOW] Method: %s | Policy: %s | Risk: %s (would have %s)", mcpReq.Method, rule.ID, rule.RiskLevel, rule.Action)
+		return nil
+
+	default:
+		if perMinute, ok := proxy.ParseRateLimit(rule.Action); ok && !v.allowRate(rule.ID, perMinute) {
+			log.Printf("[RATE-LIMIT] Policy %s exceeded %d/min, falling back to stall", rule.ID, perMinute)
+			return v.handleStall(ctx, taskID, taskState, mcpReq, rule)
+		}
+		return nil
+	}
+}
+
+// allowRate reports whether rule ruleID is still within its per-minute
+// budget, incrementing its counter as a side effect. The counter resets
+// once a minute has passed since its window started.
+func (v *VouchProxy) allowRate(ruleID string, perMinute int) bool {
+	val, _ := v.rateWindows.LoadOrStore(ruleID, &rateWindow{start: time.Now()})
+	rw := val.(*rateWindow)
+
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if time.Since(rw.start) >= time.Minute {
+		rw.start = time.Now()
+		rw.count = 0
+	}
+	rw.count++
+	return rw.count <= perMinute
+}
+
+// recentMethodsWindow bounds how many of a task's recent methods are kept
+// for `when` expressions' task.recent_methods.
+const recentMethodsWindow = 10
+
+// recordRecentMethod appends method to taskID's bounded recent-methods
+// window, read back by evaluatePolicy as task.recent_methods.
+func (v *VouchProxy) recordRecentMethod(taskID, method string) {
+	if taskID == "" {
+		return
+	}
+	var methods []string
+	if val, ok := v.recentMethodsByTask.Load(taskID); ok {
+		methods = val.([]string)
+	}
+	methods = append(methods, method)
+	if len(methods) > recentMethodsWindow {
+		methods = methods[len(methods)-recentMethodsWindow:]
+	}
+	v.recentMethodsByTask.Store(taskID, methods)
+}
+
+func (v *VouchProxy) recentMethods(taskID string) []string {
+	if val, ok := v.recentMethodsByTask.Load(taskID); ok {
+		return val.([]string)
+	}
+	return nil
 }
 
 // extractTaskMetadata parses and validates the request
@@ -171,7 +402,7 @@ func (v *VouchProxy) extractTaskMetadata(body []byte) (*MCPRequest, string, stri
 	if err := assert.Check(len(body) > 0, "request body is empty"); err != nil {
 		return nil, "", "", err
 	}
-	if err := assert.Check(len(body) < 5*1024*1024, "request body too large", "size", len(body)); err != nil {
+	if err := assert.Check(len(body) < 5*1024*1024, "request body too large: size %d", len(body)); err != nil {
 		return nil, "", "", err
 	}
 
@@ -188,7 +419,7 @@ func (v *VouchProxy) extractTaskMetadata(body []byte) (*MCPRequest, string, stri
 	taskState := "working"
 
 	if taskID != "" {
-		if err := assert.Check(len(taskID) <= 64, "task_id too long", "id", taskID); err != nil {
+		if err := assert.Check(len(taskID) <= 64, "task_id too long: id %q", taskID); err != nil {
 			return nil, "", "", err
 		}
 	}
@@ -196,21 +427,44 @@ func (v *VouchProxy) extractTaskMetadata(body []byte) (*MCPRequest, string, stri
 	return &mcpReq, taskID, taskState, nil
 }
 
-// evaluatePolicy determines the action for the request
-func (v *VouchProxy) evaluatePolicy(method string, params map[string]interface{}) (bool, *proxy.PolicyRule, error) {
+// evaluatePolicy finds the first policy rule (any action) that matches
+// this call, evaluating each candidate's method pattern, shallow
+// Conditions, and optional `when` CEL expression against the current
+// hot-reloaded ruleset. It returns nil if no rule matches (implicit
+// allow).
+func (v *VouchProxy) evaluatePolicy(taskID, method string, params map[string]interface{}) (*proxy.PolicyRule, error) {
 	if err := assert.Check(method != "", "method name required"); err != nil {
-		return false, nil, err
+		return nil, err
+	}
+	cfg := v.policy.Current()
+	if err := assert.Check(cfg != nil, "policy configuration missing"); err != nil {
+		return nil, err
 	}
-	if err := assert.Check(v.policy != nil, "policy configuration missing"); err != nil {
-		return false, nil, err
+
+	var failCount int
+	if taskID != "" {
+		failCount, _ = v.worker.GetDB().GetTaskFailureCount(taskID)
 	}
 
-	shouldStall, matchedRule := v.shouldStallMethod(method, params)
-	return shouldStall, matchedRule, nil
+	return cfg.FindMatch(proxy.EvalContext{
+		Method:           method,
+		Params:           params,
+		TaskFailureCount: failCount,
+		RecentMethods:    v.recentMethods(taskID),
+	})
 }
 
-// handleStall manages the approval workflow
-func (v *VouchProxy) handleStall(taskID, taskState string, mcpReq *MCPRequest, matchedRule *proxy.PolicyRule) error {
+// handleStall runs the FROST co-signing ceremony for a high-risk
+// "blocked" event: the daemon (participant 1) always contributes a
+// Round1 commitment and Round2 partial signature, and /api/approve/
+// collects the remaining external approvers' commitments and partials
+// until the group's threshold is met. The event is admitted to the
+// chain — via Worker.SubmitSigned — only once the aggregated signature
+// verifies against the group's aggregate public key. If ctx is canceled
+// first (the caller disconnected), the ceremony's in-memory state is
+// torn down and ctx.Err() is returned instead of blocking forever; a
+// late-arriving approval for that event is simply rejected as not found.
+func (v *VouchProxy) handleStall(ctx context.Context, taskID, taskState string, mcpReq *MCPRequest, matchedRule *proxy.PolicyRule) error {
 	if err := assert.Check(mcpReq != nil, "mcpReq must not be nil"); err != nil {
 		return err
 	}
@@ -233,10 +487,16 @@ func (v *VouchProxy) handleStall(taskID, taskState string, mcpReq *MCPRequest, m
 		RiskLevel:  matchedRule.RiskLevel,
 		WasBlocked: true,
 	}
-	v.worker.Submit(event)
 
-	approvalChan := make(chan bool, 1)
-	v.stallSignals.Store(eventID, approvalChan)
+	group := v.worker.GetSigner().GroupKey()
+	session := &stallSession{
+		event:       event,
+		threshold:   group.Threshold,
+		commitments: make(map[int]*crypto.NonceCommitment),
+		partials:    make(map[int]*crypto.PartialSignature),
+		done:        make(chan error, 1),
+	}
+	v.stallSignals.Store(eventID, session)
 
 	// Stall Intelligence
 	if taskID != "" {
@@ -246,24 +506,122 @@ func (v *VouchProxy) handleStall(taskID, taskState string, mcpReq *MCPRequest, m
 		}
 	}
 
-	log.Printf("Waiting for approval (ID: %s)...", eventID)
+	selfCommitment, err := v.worker.GetSigner().Round1(eventID)
+	if err != nil {
+		v.stallSignals.Delete(eventID)
+		return fmt.Errorf("round1: %w", err)
+	}
+	v.registerCommitment(eventID, session, selfCommitment.ParticipantID, selfCommitment)
 
-	// Demo signal (stdin or CLI)
-	go func() {
-		var input string
-		fmt.Scanln(&input)
-		if _, ok := v.stallSignals.Load(eventID); ok {
-			approvalChan <- true
+	log.Printf("Waiting for %d-of-%d approval (ID: %s)...", session.threshold, len(group.Shares), eventID)
+
+	select {
+	case err := <-session.done:
+		return err
+	case <-ctx.Done():
+		v.stallSignals.Delete(eventID)
+		return ctx.Err()
+	}
+}
+
+// registerCommitment records a participant's Round1 commitment. The first
+// time this fixes the signing set at threshold commitments, it triggers
+// the daemon's own Round2 contribution, since the daemon always
+// participates regardless of which submission completed the set. Once
+// the set is fixed, any later commitment (e.g. a slow extra approver in
+// an n>threshold group) is dropped rather than added to the map: every
+// Round2 partial already collected (or still to be collected) was/will
+// be computed against the exact signing set that was fixed, and FROST's
+// binding factors and group commitment depend on that set being
+// identical for every participant — mutating it after the fact would
+// make the aggregated signature fail group.Verify.
+func (v *VouchProxy) registerCommitment(sessionID string, session *stallSession, participantID int, c *crypto.NonceCommitment) {
+	session.mu.Lock()
+	if session.fixed {
+		session.mu.Unlock()
+		log.Printf("[STALL] %s: ignoring commitment from participant %d, signing set already fixed", sessionID, participantID)
+		return
+	}
+	session.commitments[participantID] = c
+	justFixed := len(session.commitments) >= session.threshold
+	if justFixed {
+		session.fixed = true
+	}
+	session.mu.Unlock()
+
+	if justFixed {
+		if err := v.contributeSelfPartial(sessionID, session); err != nil {
+			log.Printf("[STALL] %s: daemon round2 failed: %v", sessionID, err)
 		}
-	}()
+	}
+}
 
-	if !<-approvalChan {
-		return fmt.Errorf("stall rejected")
+// contributeSelfPartial computes and registers the daemon's own Round2
+// partial signature over the fixed signing set.
+func (v *VouchProxy) contributeSelfPartial(sessionID string, session *stallSession) error {
+	session.mu.Lock()
+	commitments := make([]*crypto.NonceCommitment, 0, len(session.commitments))
+	for _, c := range session.commitments {
+		commitments = append(commitments, c)
 	}
+	digest := store.ApprovalDigest(session.event.ID, session.event.EventType, session.event.Method, session.event.TaskID, session.event.PolicyID, session.event.Params)
+	session.mu.Unlock()
 
+	partial, err := v.worker.GetSigner().Round2(sessionID, digest, commitments)
+	if err != nil {
+		return err
+	}
+	v.registerPartialAndMaybeAdmit(sessionID, session, partial)
 	return nil
 }
 
+// registerPartialAndMaybeAdmit records a participant's Round2 partial
+// signature and, once threshold partials have been collected, aggregates
+// them, verifies the result against the group's aggregate public key,
+// and submits the now-co-signed event onto the chain.
+func (v *VouchProxy) registerPartialAndMaybeAdmit(sessionID string, session *stallSession, partial *crypto.PartialSignature) {
+	session.mu.Lock()
+	session.partials[partial.ParticipantID] = partial
+	ready := len(session.partials) >= session.threshold
+	var commitments []*crypto.NonceCommitment
+	var partials []*crypto.PartialSignature
+	if ready {
+		for _, c := range session.commitments {
+			commitments = append(commitments, c)
+		}
+		for _, p := range session.partials {
+			partials = append(partials, p)
+		}
+	}
+	session.mu.Unlock()
+
+	if !ready {
+		return
+	}
+
+	group := v.worker.GetSigner().GroupKey()
+	digest := store.ApprovalDigest(session.event.ID, session.event.EventType, session.event.Method, session.event.TaskID, session.event.PolicyID, session.event.Params)
+
+	sig, err := crypto.AggregateSignature(group, commitments, digest, partials)
+	if err == nil && !group.Verify(digest, sig) {
+		err = fmt.Errorf("aggregated threshold signature failed self-verification")
+	}
+	if err != nil {
+		v.stallSignals.Delete(sessionID)
+		session.done <- fmt.Errorf("aggregate threshold signature: %w", err)
+		return
+	}
+
+	if err := v.worker.SubmitSigned(session.event, sig); err != nil {
+		v.stallSignals.Delete(sessionID)
+		session.done <- fmt.Errorf("admit co-signed event: %w", err)
+		return
+	}
+
+	v.stallSignals.Delete(sessionID)
+	session.done <- nil
+}
+
 // submitToolCallEvent prepares and sends the tool_call event to the ledger
 func (v *VouchProxy) submitToolCallEvent(taskID, taskState string, mcpReq *MCPRequest, matchedRule *proxy.PolicyRule) {
 	_ = assert.Check(mcpReq != nil, "mcpReq must not be nil")
@@ -316,17 +674,32 @@ func (v *VouchProxy) interceptResponse(resp *http.Response) error {
 	}
 	resp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 
+	v.handleResponseBody(bodyBytes)
+	return nil
+}
+
+// HandleResponse implements transport.MessageHandler for the stdio and
+// streamable-HTTP transports.
+func (v *VouchProxy) HandleResponse(body []byte) {
+	v.handleResponseBody(body)
+}
+
+// handleResponseBody parses a JSON-RPC response or notification, updates
+// task state, and submits the tool_response event to the ledger. It is
+// shared by every transport, each of which forwards body unmodified
+// regardless of what this does.
+func (v *VouchProxy) handleResponseBody(bodyBytes []byte) {
 	// Try to parse as MCP response
 	var mcpResp MCPResponse
 	if err := json.Unmarshal(bodyBytes, &mcpResp); err != nil {
 		// Not a JSON-RPC response, skip
-		return nil
+		return
 	}
 
 	// Health Sentinel: Check if ledger is healthy
 	if !v.worker.IsHealthy() {
 		log.Printf("[CRITICAL] Dropping response event: Ledger Unhealthy")
-		return nil
+		return
 	}
 
 	// Check for task information in response
@@ -358,53 +731,22 @@ func (v *VouchProxy) interceptResponse(resp *http.Response) error {
 
 	// Send to async worker
 	v.worker.Submit(event)
-
-	return nil
 }
 
-// shouldStallMethod checks if a method should be stalled based on policy
-func (v *VouchProxy) shouldStallMethod(method string, params map[string]interface{}) (bool, *proxy.PolicyRule) {
-	if err := assert.Check(method != "", "method name must not be empty"); err != nil {
-		return false, nil
-	}
-
-	for _, rule := range v.policy.Policies {
-		if rule.Action != "stall" {
-			continue
-		}
-
-		// Check method match with wildcard support
-		for _, pattern := range rule.MatchMethods {
-			if proxy.MatchPattern(pattern, method) {
-				// Check additional conditions if present
-				if rule.Conditions != nil {
-					if !proxy.CheckConditions(rule.Conditions, params) {
-						continue
-					}
-				}
-				return true, &rule
-			}
-		}
-	}
-	return false, nil
-}
-
-// sendErrorResponse sends a JSON-RPC error response and short-circuits the proxy
-func (v *VouchProxy) sendErrorResponse(req *http.Request, statusCode int, code int, message string) {
-	errorResp := MCPResponse{
+// jsonRPCError builds the JSON-RPC error response body sent straight
+// back to the caller in place of forwarding a blocked or rejected
+// request — via a synthesized *http.Response on the HTTP transport (see
+// jsonRPCResponse), or directly on the stdio/streamable transports.
+func jsonRPCError(code int, message string) []byte {
+	resp := MCPResponse{
 		JSONRPC: "2.0",
-		ID:      nil,
 		Error: map[string]interface{}{
 			"code":    code,
 			"message": message,
 		},
 	}
-
-	respBytes, _ := json.Marshal(errorResp)
-	log.Printf("[SECURITY] Blocking agent request due to ledger failure: %s (JSON: %s)", message, string(respBytes))
-
-	// Implementation note: Short-circuiting from Director requires hijacking or RoundTripper.
-	// For now, we log it clearly which meets the "Fail-Awareness" requirement for the demo.
+	respBytes, _ := json.Marshal(resp)
+	return respBytes
 }
 
 // redactParams removes sensitive keys from parameters
@@ -427,73 +769,234 @@ func redactParams(params map[string]interface{}, keys []string) map[string]inter
 	return redacted
 }
 
-// handleRekey handles key rotation requests
+// rekeyRequestTokenHeader carries the shared secret set by --rekey-token;
+// handleRekey refuses every request unless it matches exactly.
+const rekeyRequestTokenHeader = "X-Vouch-Rekey-Token"
+
+// rekeyRequest carries every participant's current share so handleRekey
+// can run a proactive resharing ceremony; a degenerate 1-of-1 group
+// (the out-of-the-box default) supplies none of these and rekeys against
+// only the daemon's own share. ParticipantID names which participant the
+// caller is, so the response can hand back only that participant's own
+// refreshed share.
+type rekeyRequest struct {
+	ParticipantID int          `json:"participant_id"`
+	Shares        []rekeyShare `json:"shares"`
+}
+type rekeyShare struct {
+	ParticipantID int    `json:"participant_id"`
+	Secret        string `json:"secret"`
+}
+type rekeyResponse struct {
+	Share     *rekeyShare `json:"share,omitempty"`
+	PublicKey string      `json:"public_key"`
+}
+
+// handleRekey runs a proactive share refresh: every participant's share
+// is re-randomized against a fresh zero-constant-term polynomial, so a
+// past leak of any t-1 shares stops being useful, while the aggregate
+// public key — and therefore every past chain signature's verifiability
+// — is unchanged.
+//
+// The request must carry the --rekey-token shared secret, and each
+// supplied share is checked against the group's existing verification
+// share by crypto.RefreshShares, so a caller can neither trigger a rekey
+// nor fold in a fabricated share without already holding the real one.
+// The response returns only the calling participant's own refreshed
+// share, never the other participants'; those are expected to reach their
+// owners out of band, the same way the initial shares were distributed.
 func (v *VouchProxy) handleRekey(w http.ResponseWriter, r *http.Request) {
-	oldPubKey, newPubKey, err := v.worker.GetSigner().RotateKey(".vouch_key")
+	if v.rekeyToken == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get(rekeyRequestTokenHeader)), []byte(v.rekeyToken)) != 1 {
+		http.Error(w, "missing or invalid "+rekeyRequestTokenHeader, http.StatusUnauthorized)
+		return
+	}
+
+	var req rekeyRequest
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&req)
+	}
+
+	signer := v.worker.GetSigner()
+	group := signer.GroupKey()
+
+	var allShares []*crypto.Share
+	if len(req.Shares) > 0 {
+		for _, rs := range req.Shares {
+			secret, err := crypto.DecodeScalarHex(rs.Secret)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("malformed share for participant %d: %v", rs.ParticipantID, err), http.StatusBadRequest)
+				return
+			}
+			allShares = append(allShares, &crypto.Share{ParticipantID: rs.ParticipantID, Secret: secret})
+		}
+	} else if group.Threshold == 1 && len(group.Shares) == 1 {
+		req.ParticipantID = signer.Share().ParticipantID
+		allShares = []*crypto.Share{signer.Share()}
+	} else {
+		http.Error(w, "rekey of a t>1 group requires every participant's current share", http.StatusBadRequest)
+		return
+	}
+
+	refreshed, newGroup, err := signer.Rekey(".vouch_key", allShares)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to rotate key: %v", err), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("rekey failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("KEY REFRESH SUCCESSFUL (aggregate public key unchanged: %s)", hex.EncodeToString(newGroup.PublicKey))
+
+	resp := rekeyResponse{PublicKey: hex.EncodeToString(newGroup.PublicKey)}
+	for _, s := range refreshed {
+		if s.ParticipantID == req.ParticipantID {
+			resp.Share = &rekeyShare{ParticipantID: s.ParticipantID, Secret: crypto.EncodeScalarHex(s.Secret)}
+			break
+		}
+	}
+	if resp.Share == nil {
+		http.Error(w, fmt.Sprintf("participant %d is not among the resharing set", req.ParticipantID), http.StatusBadRequest)
 		return
 	}
+	writeJSON(w, resp)
+}
+
+// commitmentWire and partialWire are the hex-encoded wire forms of
+// crypto.NonceCommitment and crypto.PartialSignature.
+type commitmentWire struct {
+	Hiding  string `json:"hiding"`
+	Binding string `json:"binding"`
+}
+type partialWire struct {
+	Z string `json:"z"`
+}
 
-	log.Printf("KEY ROTATION SUCCESSFUL")
-	log.Printf("Old Public Key: %s", oldPubKey)
-	log.Printf("New Public Key: %s", newPubKey)
+// approvalPayload is the body of a single /api/approve/ request: a
+// participant submits exactly one of Commitment (Round1) or Partial
+// (Round2), never both.
+type approvalPayload struct {
+	ParticipantID int             `json:"participant_id"`
+	Commitment    *commitmentWire `json:"commitment,omitempty"`
+	Partial       *partialWire    `json:"partial,omitempty"`
+}
 
-	_, _ = fmt.Fprintf(w, "Key rotated successfully\nOld: %s\nNew: %s", oldPubKey, newPubKey)
+// approvalResponse reports the current state of the signing ceremony.
+// Once "fixed", Commitments lists the complete signing set so a
+// participant can compute its Round2 partial against it.
+type approvalResponse struct {
+	Status      string                  `json:"status"`
+	Commitments map[int]*commitmentWire `json:"commitments,omitempty"`
 }
 
-// handleApprove handles approval requests from the CLI
+// handleApprove accepts one participant's contribution to a blocked
+// event's FROST co-signing ceremony: a Round1 nonce commitment, or —
+// once the signing set is fixed — a Round2 partial signature.
 func (v *VouchProxy) handleApprove(w http.ResponseWriter, r *http.Request) {
-	// Extract event ID from URL path
 	eventID := strings.TrimPrefix(r.URL.Path, "/api/approve/")
-
 	if eventID == "" {
 		http.Error(w, "Event ID required", http.StatusBadRequest)
 		return
 	}
 
-	// Look up the approval channel
 	val, ok := v.stallSignals.Load(eventID)
 	if !ok {
 		http.Error(w, "Event not found or already processed", http.StatusNotFound)
 		return
 	}
+	session := val.(*stallSession)
 
-	approvalChan := val.(chan bool)
+	var payload approvalPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, fmt.Sprintf("malformed approval payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case payload.Commitment != nil:
+		hiding, err := crypto.DecodePointHex(payload.Commitment.Hiding)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("malformed hiding commitment: %v", err), http.StatusBadRequest)
+			return
+		}
+		binding, err := crypto.DecodePointHex(payload.Commitment.Binding)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("malformed binding commitment: %v", err), http.StatusBadRequest)
+			return
+		}
+		v.registerCommitment(eventID, session, payload.ParticipantID, &crypto.NonceCommitment{
+			ParticipantID: payload.ParticipantID, Hiding: hiding, Binding: binding,
+		})
+		log.Printf("Event %s: participant %d registered Round1 commitment", eventID, payload.ParticipantID)
+
+	case payload.Partial != nil:
+		session.mu.Lock()
+		fixed := session.fixed
+		session.mu.Unlock()
+		if !fixed {
+			http.Error(w, "signing set not yet fixed; submit a commitment first", http.StatusConflict)
+			return
+		}
+		z, err := crypto.DecodeScalarHex(payload.Partial.Z)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("malformed partial signature: %v", err), http.StatusBadRequest)
+			return
+		}
+		v.registerPartialAndMaybeAdmit(eventID, session, &crypto.PartialSignature{
+			ParticipantID: payload.ParticipantID, Z: z,
+		})
+		log.Printf("Event %s: participant %d registered Round2 partial", eventID, payload.ParticipantID)
 
-	// Send approval signal
-	select {
-	case approvalChan <- true:
-		log.Printf("Event %s approved via CLI", eventID)
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("Event approved\n"))
 	default:
-		http.Error(w, "Event already processed", http.StatusConflict)
+		http.Error(w, "payload must set either commitment or partial", http.StatusBadRequest)
+		return
+	}
+
+	session.mu.Lock()
+	fixed := session.fixed
+	session.mu.Unlock()
+
+	resp := approvalResponse{Status: "pending"}
+	if fixed {
+		resp.Status = "fixed"
+		resp.Commitments = encodeCommitments(session)
 	}
+	writeJSON(w, resp)
 }
 
-// handleReject handles rejection requests from the CLI
+// encodeCommitments returns the ceremony's current commitment set in
+// wire form, for a participant computing its own Round2 partial.
+func encodeCommitments(session *stallSession) map[int]*commitmentWire {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	out := make(map[int]*commitmentWire, len(session.commitments))
+	for id, c := range session.commitments {
+		out[id] = &commitmentWire{Hiding: crypto.EncodePointHex(c.Hiding), Binding: crypto.EncodePointHex(c.Binding)}
+	}
+	return out
+}
+
+// writeJSON writes v as the JSON response body.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// handleReject aborts a blocked event's co-signing ceremony.
 func (v *VouchProxy) handleReject(w http.ResponseWriter, r *http.Request) {
-	// Extract event ID from URL path
 	eventID := strings.TrimPrefix(r.URL.Path, "/api/reject/")
-
 	if eventID == "" {
 		http.Error(w, "Event ID required", http.StatusBadRequest)
 		return
 	}
 
-	// Look up the approval channel
 	val, ok := v.stallSignals.Load(eventID)
 	if !ok {
 		http.Error(w, "Event not found or already processed", http.StatusNotFound)
 		return
 	}
+	session := val.(*stallSession)
+	v.stallSignals.Delete(eventID)
 
-	approvalChan := val.(chan bool)
-
-	// Send rejection signal (false)
 	select {
-	case approvalChan <- false:
+	case session.done <- fmt.Errorf("stall rejected"):
 		log.Printf("Event %s rejected via CLI", eventID)
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("Event rejected\n"))
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourname/vouch/internal/crypto"
+	"github.com/yourname/vouch/internal/ledger"
+	"github.com/yourname/vouch/internal/ledger/store"
+	"github.com/yourname/vouch/internal/proxy"
+)
+
+const stallPolicyYAML = `
+version: "1"
+policies:
+  - id: high-risk-write
+    match_methods:
+      - "file.write"
+    action: stall
+    risk_level: high
+`
+
+const emptyPolicyYAML = `
+version: "1"
+policies: []
+`
+
+// newTestPolicy loads policy YAML from a temp file via the real
+// proxy.LoadPolicy, so tests exercise the same parsing and compilation
+// path as production rather than hand-building a PolicyConfig.
+func newTestPolicy(t *testing.T, yaml string) *proxy.PolicyStore {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "vouch-policy.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatalf("write policy file: %v", err)
+	}
+	store, err := proxy.LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	return store
+}
+
+// newTestWorker builds a Worker over a fresh in-memory SQLite backend and
+// a threshold-of-n FROST group. It does not call Start, so callers that
+// want a healthy worker must do so themselves.
+func newTestWorker(t *testing.T, n, threshold int) *ledger.Worker {
+	t.Helper()
+
+	dir := t.TempDir()
+	paths := make([]string, n)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("vouch-%d.key", i))
+	}
+	if _, err := crypto.BootstrapThresholdGroup(n, threshold, paths); err != nil {
+		t.Fatalf("bootstrap threshold group: %v", err)
+	}
+
+	backend, err := store.NewSQLiteBackend(":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite backend: %v", err)
+	}
+	t.Cleanup(func() { backend.Close() })
+
+	worker, err := ledger.NewWorker(16, backend, paths[0])
+	if err != nil {
+		t.Fatalf("new worker: %v", err)
+	}
+	return worker
+}
+
+func newTestProxyWithWorker(worker *ledger.Worker, policy *proxy.PolicyStore) *VouchProxy {
+	return &VouchProxy{
+		worker:              worker,
+		activeTasks:         &sync.Map{},
+		policy:              policy,
+		stallSignals:        &sync.Map{},
+		lastEventByTask:     &sync.Map{},
+		recentMethodsByTask: &sync.Map{},
+		rateWindows:         &sync.Map{},
+	}
+}
+
+// waitForStallSession polls v.stallSignals until a session appears,
+// returning its event ID.
+func waitForStallSession(t *testing.T, v *VouchProxy) string {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		var found string
+		v.stallSignals.Range(func(key, _ interface{}) bool {
+			found = key.(string)
+			return false
+		})
+		if found != "" {
+			return found
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for a stall session to appear")
+	return ""
+}
+
+func decodeRPCError(t *testing.T, resp *http.Response) map[string]interface{} {
+	t.Helper()
+	var rpcResp MCPResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		t.Fatalf("decode JSON-RPC response: %v", err)
+	}
+	if rpcResp.Error == nil {
+		t.Fatalf("expected a JSON-RPC error, got %+v", rpcResp)
+	}
+	return rpcResp.Error
+}
+
+// TestRoundTrip_LedgerUnhealthyReturns503 confirms a block for ledger
+// unhealthiness returns 503 with a JSON-RPC error and never dials
+// upstream, i.e. RoundTrip truly short-circuits rather than just logging.
+func TestRoundTrip_LedgerUnhealthyReturns503(t *testing.T) {
+	upstreamHit := false
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamHit = true
+	}))
+	defer upstream.Close()
+
+	// Worker is never Start()-ed, so IsHealthy() is false.
+	worker := newTestWorker(t, 1, 1)
+	v := newTestProxyWithWorker(worker, newTestPolicy(t, emptyPolicyYAML))
+
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"file.write","params":{}}`)
+	req := httptest.NewRequest(http.MethodPost, upstream.URL, bytes.NewReader(body))
+
+	resp, err := v.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	decodeRPCError(t, resp)
+
+	if upstreamHit {
+		t.Fatal("a ledger-unhealthy block must never contact upstream")
+	}
+}
+
+// TestRoundTrip_RejectedStallNeverContactsUpstream confirms a stalled
+// request that is rejected via /api/reject/ returns 403 with a JSON-RPC
+// error and never dials upstream.
+func TestRoundTrip_RejectedStallNeverContactsUpstream(t *testing.T) {
+	upstreamHit := false
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamHit = true
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	}))
+	defer upstream.Close()
+
+	// A 2-of-2 group means the daemon's own Round1 commitment alone
+	// cannot fix the signing set, so the ceremony stays pending until an
+	// approval (or, here, a rejection) arrives.
+	worker := newTestWorker(t, 2, 2)
+	if err := worker.Start(); err != nil {
+		t.Fatalf("start worker: %v", err)
+	}
+	v := newTestProxyWithWorker(worker, newTestPolicy(t, stallPolicyYAML))
+
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"file.write","params":{}}`)
+	req := httptest.NewRequest(http.MethodPost, upstream.URL, bytes.NewReader(body))
+
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		resp, err := v.RoundTrip(req)
+		done <- result{resp, err}
+	}()
+
+	eventID := waitForStallSession(t, v)
+
+	rejectW := httptest.NewRecorder()
+	v.handleReject(rejectW, httptest.NewRequest(http.MethodPost, "/api/reject/"+eventID, nil))
+	if rejectW.Code != http.StatusOK {
+		t.Fatalf("reject: status %d, body %s", rejectW.Code, rejectW.Body)
+	}
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatalf("RoundTrip returned error: %v", r.err)
+		}
+		if r.resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", r.resp.StatusCode, http.StatusForbidden)
+		}
+		decodeRPCError(t, r.resp)
+	case <-time.After(2 * time.Second):
+		t.Fatal("RoundTrip did not return after the stall was rejected")
+	}
+
+	if upstreamHit {
+		t.Fatal("a rejected stall must never contact upstream")
+	}
+}
+
+// TestRoundTrip_ApprovedStallForwardsBodyUnmodified confirms that once a
+// stall is approved (here, a 1-of-1 group that self-approves
+// immediately), the original request body reaches upstream byte-for-byte.
+func TestRoundTrip_ApprovedStallForwardsBodyUnmodified(t *testing.T) {
+	var gotBody []byte
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	}))
+	defer upstream.Close()
+
+	worker := newTestWorker(t, 1, 1)
+	if err := worker.Start(); err != nil {
+		t.Fatalf("start worker: %v", err)
+	}
+	v := newTestProxyWithWorker(worker, newTestPolicy(t, stallPolicyYAML))
+
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"file.write","params":{"path":"/etc/passwd"}}`)
+	req := httptest.NewRequest(http.MethodPost, upstream.URL, bytes.NewReader(body))
+
+	resp, err := v.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if !bytes.Equal(gotBody, body) {
+		t.Fatalf("upstream received body %q, want %q", gotBody, body)
+	}
+}